@@ -1,6 +1,7 @@
 package php
 
 import (
+	"fmt"
 	"reflect"
 	"sort"
 )
@@ -125,6 +126,82 @@ func ArrayUnique(array interface{}) interface{} {
 	return ArrayKeys(res)
 }
 
+// ArrayKeysValue is ArrayKeys returning the keys as a list Value in order
+//
+// array can be a Value or anything NewValue converts to an array, it fails for the other values.
+// .eg ArrayKeysValue(map[string]int{"b": 2, "a": 1}) returns the list ["a", "b"]
+func ArrayKeysValue(array interface{}) (Value, error) {
+	arr, err := arrayValue(array)
+	if err != nil {
+		return Value{}, err
+	}
+	res := NewArray()
+	for _, k := range arr.Keys() {
+		res.Append(k)
+	}
+	return res, nil
+}
+
+// ArrayValuesValue is ArrayValues returning the elements as a list Value in order
+func ArrayValuesValue(array interface{}) (Value, error) {
+	arr, err := arrayValue(array)
+	if err != nil {
+		return Value{}, err
+	}
+	res := NewArray()
+	for _, v := range arr.Values() {
+		res.Append(v)
+	}
+	return res, nil
+}
+
+// ArrayFlipValue is ArrayFilp returning an array Value, the elements which are not int or string are
+// skipped like PHP and a later key wins for the same element
+// .eg ArrayFlipValue([]string{"a", "b", "a"}) returns ["a" => 2, "b" => 1]
+func ArrayFlipValue(array interface{}) (Value, error) {
+	arr, err := arrayValue(array)
+	if err != nil {
+		return Value{}, err
+	}
+	res := NewArray()
+	arr.Range(func(key, value Value) bool {
+		if value.kind == KindInt || value.kind == KindString {
+			res.Set(value, key)
+		}
+		return true
+	})
+	return res, nil
+}
+
+// ArrayUniqueValue is ArrayUnique returning an array Value, it keeps the first element of the elements
+// which are equal as strings with their keys, like PHP's array_unique with SORT_STRING
+// .eg ArrayUniqueValue([]interface{}{1, "1", 2}) returns [0 => 1, 2 => 2]
+func ArrayUniqueValue(array interface{}) (Value, error) {
+	arr, err := arrayValue(array)
+	if err != nil {
+		return Value{}, err
+	}
+	res := NewArray()
+	seen := make(map[string]bool, arr.Len())
+	arr.Range(func(key, value Value) bool {
+		if s := value.String(); !seen[s] {
+			seen[s] = true
+			res.Set(key, value)
+		}
+		return true
+	})
+	return res, nil
+}
+
+// arrayValue converts array to an array Value for the Value variants of the array functions
+func arrayValue(array interface{}) (Value, error) {
+	arr := NewValue(array)
+	if arr.kind != KindArray {
+		return Value{}, fmt.Errorf("expects parameter 1 to be array, %s given", arr.kind)
+	}
+	return arr, nil
+}
+
 // Sort can only sort []int, []string, []float64
 func Sort(array interface{}) {
 	t, v, _ := getCommon(array)
@@ -148,7 +225,14 @@ func Sort(array interface{}) {
 	}
 }
 
+// getCommon returns the reflection of array, a Value is converted with Value.Interface first
 func getCommon(array interface{}) (reflect.Type, reflect.Value, int) {
+	switch x := array.(type) {
+	case Value:
+		array = x.Interface()
+	case *Value:
+		array = x.Interface()
+	}
 	t := reflect.TypeOf(array)
 	v := reflect.ValueOf(array)
 	l := v.Len()
@@ -0,0 +1,274 @@
+package php
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// modes of MbConvertCase
+const (
+	MbCaseUpper = iota
+	MbCaseLower
+	MbCaseTitle
+	MbCaseFold
+	MbCaseUpperSimple
+	MbCaseLowerSimple
+	MbCaseTitleSimple
+	MbCaseFoldSimple
+)
+
+// Ucwords uppercase the first character of each word in a string
+//
+// Words are separated by the characters of delimiters, " \t\r\n\f\v" by default, ranges such as "a..z"
+// are allowed like in Trim. Like PHP it works on bytes, only the ASCII letters a to z are uppercased and
+// the other characters are left untouched, use MbConvertCase with MbCaseTitle for Unicode text.
+// .eg Ucwords("hello world-foo bar") returns "Hello World-foo Bar", Ucwords("hello|world", "|") returns "Hello|World",
+// Ucwords("élan vital") returns "élan Vital"
+//
+// see http://php.net/manual/en/function.ucwords.php
+func Ucwords(str string, delimiters ...string) string {
+	mask := charMask(" \t\r\n\f\v")
+	if len(delimiters) > 0 {
+		mask = charMask(delimiters[0])
+	}
+	b := []byte(str)
+	start := true
+	for i, c := range b {
+		if start && 'a' <= c && c <= 'z' {
+			b[i] = c - 'a' + 'A'
+		}
+		start = mask[c]
+	}
+	return string(b)
+}
+
+// StrWordCount return information about words used in a string
+//
+// A word is a run of letters, "'" and "-", plus the characters of the optional charlist. It is multi-byte
+// safe, any Unicode letter counts and the positions are counted in characters.
+// format 0 returns the number of words as int, 1 returns the words as []string and 2 returns a
+// map[int]string of the words keyed by their position, nil is returned for an unknown format.
+// .eg StrWordCount("Hello fri3nd, you're looking good today!", 0) returns 7,
+// StrWordCount("Hello fri3nd, you're", 2) returns map[0:Hello 6:fri 10:nd 14:you're],
+// StrWordCount("Hello fri3nd", 1, "0..9") returns [Hello fri3nd]
+//
+// see http://php.net/manual/en/function.str-word-count.php
+func StrWordCount(str string, format int, charlist ...string) interface{} {
+	if format < 0 || format > 2 {
+		return nil
+	}
+	inList := func(rune) bool { return false }
+	if len(charlist) > 0 && charlist[0] != "" {
+		inList = runeMask(charlist[0])
+	}
+
+	rs := []rune(str)
+	p, e := 0, len(rs)
+	// the first character cannot be ' or - and the last one cannot be -, unless allowed by charlist
+	if p < e && (rs[p] == '\'' && !inList('\'') || rs[p] == '-' && !inList('-')) {
+		p++
+	}
+	if p < e && rs[e-1] == '-' && !inList('-') {
+		e--
+	}
+
+	count, words, positions := 0, []string{}, map[int]string{}
+	for p < e {
+		s := p
+		for p < e && (unicode.IsLetter(rs[p]) || inList(rs[p]) || rs[p] == '\'' || rs[p] == '-') {
+			p++
+		}
+		if p > s {
+			switch format {
+			case 1:
+				words = append(words, string(rs[s:p]))
+			case 2:
+				positions[s] = string(rs[s:p])
+			default:
+				count++
+			}
+		}
+		p++
+	}
+
+	switch format {
+	case 1:
+		return words
+	case 2:
+		return positions
+	}
+	return count
+}
+
+// MbConvertCase perform case folding on a string
+//
+// mode is one of MbCaseUpper, MbCaseLower, MbCaseTitle, MbCaseFold and their simple variants. The full
+// modes may change the length of the string, .eg "ß" becomes "SS", while the simple ones map each
+// character to exactly one character. In title modes a character is title cased if the previous
+// character, ignoring apostrophes and other case-ignorable characters, is not a cased letter, and
+// lower cased otherwise.
+// .eg MbConvertCase("hello wORLD, o'neil", MbCaseTitle) returns "Hello World, O'neil"
+//
+// see http://php.net/manual/en/function.mb-convert-case.php
+func MbConvertCase(str string, mode int) string {
+	switch mode {
+	case MbCaseUpper:
+		return cases.Upper(language.Und).String(str)
+	case MbCaseLower:
+		return cases.Lower(language.Und).String(str)
+	case MbCaseFold:
+		return cases.Fold().String(str)
+	case MbCaseUpperSimple:
+		return strings.Map(unicode.ToUpper, str)
+	case MbCaseLowerSimple:
+		return strings.Map(unicode.ToLower, str)
+	case MbCaseFoldSimple:
+		return strings.Map(foldSimple, str)
+	case MbCaseTitle, MbCaseTitleSimple:
+		return titleCase([]rune(str), mode == MbCaseTitle)
+	}
+	return str
+}
+
+// specialTitle holds the title case mappings of SpecialCasing.txt which are not one character
+var specialTitle = map[rune]string{
+	'ß': "Ss", 'ŉ': "ʼN", 'ﬀ': "Ff", 'ﬁ': "Fi", 'ﬂ': "Fl", 'ﬃ': "Ffi", 'ﬄ': "Ffl", 'ﬅ': "St", 'ﬆ': "St",
+}
+
+// titleCase is the port of the title modes of PHP's php_unicode_convert_case, full applies the
+// special casing rules of SpecialCasing.txt including the final form of sigma
+func titleCase(rs []rune, full bool) string {
+	var b strings.Builder
+	b.Grow(len(rs))
+	titleMode := false
+	for i, r := range rs {
+		switch {
+		case !titleMode && full && specialTitle[r] != "":
+			b.WriteString(specialTitle[r])
+		case !titleMode:
+			b.WriteRune(unicode.ToTitle(r))
+		case full && r == 'İ':
+			b.WriteString("i\u0307")
+		case full && r == 'Σ' && !followedByCased(rs[i+1:]):
+			b.WriteRune('ς')
+		default:
+			b.WriteRune(unicode.ToLower(r))
+		}
+		if !isCaseIgnorable(r) {
+			titleMode = isCased(r)
+		}
+	}
+	return b.String()
+}
+
+// followedByCased reports if the first character of rs which is not case-ignorable is cased
+func followedByCased(rs []rune) bool {
+	for _, r := range rs {
+		if !isCaseIgnorable(r) {
+			return isCased(r)
+		}
+	}
+	return false
+}
+
+// isCased reports if r has the Unicode property Cased
+func isCased(r rune) bool {
+	return unicode.IsUpper(r) || unicode.IsLower(r) || unicode.IsTitle(r) ||
+		unicode.In(r, unicode.Other_Lowercase, unicode.Other_Uppercase)
+}
+
+// isCaseIgnorable reports if r has the Unicode property Case_Ignorable
+func isCaseIgnorable(r rune) bool {
+	switch r {
+	// Word_Break MidLetter, MidNumLet and Single_Quote
+	case '\'', '.', ':', '\u00B7', '\u0387', '\u055F', '\u05F4', '\u2018', '\u2019', '\u2024', '\u2027',
+		'\uFE13', '\uFE52', '\uFE55', '\uFF07', '\uFF0E', '\uFF1A':
+		return true
+	}
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf, unicode.Lm, unicode.Sk)
+}
+
+// foldSimple is the simple case folding of CaseFolding.txt
+func foldSimple(r rune) rune {
+	switch {
+	case r == 'İ' || r == 'ı':
+		return r
+	case unicode.Is(unicode.Cherokee, r):
+		return unicode.ToUpper(r)
+	}
+	return unicode.ToLower(unicode.ToUpper(r))
+}
+
+// CamelCase convert a string to camelCase, see caseWords for how words are split
+// .eg CamelCase("user_id") returns "userId", CamelCase("HTTPServer") returns "httpServer"
+func CamelCase(str string) string {
+	words := caseWords(str)
+	for i, word := range words {
+		if i == 0 {
+			words[i] = strings.ToLower(word)
+		} else {
+			words[i] = Ucfirst(strings.ToLower(word))
+		}
+	}
+	return strings.Join(words, "")
+}
+
+// StudlyCase convert a string to StudlyCase, see caseWords for how words are split
+// .eg StudlyCase("user_id") returns "UserId", StudlyCase("xml-http-request") returns "XmlHttpRequest"
+func StudlyCase(str string) string {
+	words := caseWords(str)
+	for i, word := range words {
+		words[i] = Ucfirst(strings.ToLower(word))
+	}
+	return strings.Join(words, "")
+}
+
+// SnakeCase convert a string to snake_case, see caseWords for how words are split
+// .eg SnakeCase("HTTPServer") returns "http_server", SnakeCase("userID") returns "user_id"
+func SnakeCase(str string) string {
+	return strings.ToLower(strings.Join(caseWords(str), "_"))
+}
+
+// KebabCase convert a string to kebab-case, see caseWords for how words are split
+// .eg KebabCase("XMLHttpRequest") returns "xml-http-request", KebabCase("user_id") returns "user-id"
+func KebabCase(str string) string {
+	return strings.ToLower(strings.Join(caseWords(str), "-"))
+}
+
+// caseWords split str into words for the case converters
+//
+// Any character other than a letter or a digit separates words. A word also ends before an upper case
+// letter following a lower case letter or a digit, and before the last capital of an acronym followed
+// by a lower case letter, so "getHTTPResponse2Code" is split into "get", "HTTP", "Response2" and "Code".
+func caseWords(str string) []string {
+	rs := []rune(str)
+	words := []string{}
+	start := -1
+	for i, r := range rs {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				words = append(words, string(rs[start:i]))
+				start = -1
+			}
+			continue
+		}
+		if start >= 0 && unicode.IsUpper(r) {
+			prev := rs[i-1]
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+				unicode.IsUpper(prev) && i+1 < len(rs) && unicode.IsLower(rs[i+1]) {
+				words = append(words, string(rs[start:i]))
+				start = i
+			}
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, string(rs[start:]))
+	}
+	return words
+}
@@ -0,0 +1,108 @@
+package php
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUcwords(t *testing.T) {
+	tests := []struct {
+		str, delimiters, want string
+	}{
+		{"hello world-foo bar", "", "Hello World-foo Bar"},
+		{"hello|world", "|", "Hello|World"},
+		{"HELLO world", "", "HELLO World"},
+		{"hello_world-foo", "_-", "Hello_World-Foo"},
+		{"a\tb\nc\rd\fe\vf", "", "A\tB\nC\rD\fE\vF"},
+		{"élan vital", "", "élan Vital"},
+		{"ünter öl", "", "ünter öl"},
+		{"a·b", "·", "A·B"},
+		{"a b", "a..z", "A b"},
+		{"", "", ""},
+	}
+	for _, test := range tests {
+		delimiters := []string{test.delimiters}
+		if test.delimiters == "" {
+			delimiters = nil
+		}
+		if got := Ucwords(test.str, delimiters...); got != test.want {
+			t.Errorf("Ucwords(%q, %q) = %q, want %q", test.str, test.delimiters, got, test.want)
+		}
+	}
+}
+
+func TestStrWordCount(t *testing.T) {
+	const str = "Hello fri3nd, you're looking good today!"
+	if got := StrWordCount(str, 0); !reflect.DeepEqual(got, 7) {
+		t.Errorf("StrWordCount(%q, 0) = %v, want 7", str, got)
+	}
+	words := []string{"Hello", "fri", "nd", "you're", "looking", "good", "today"}
+	if got := StrWordCount(str, 1); !reflect.DeepEqual(got, words) {
+		t.Errorf("StrWordCount(%q, 1) = %v, want %v", str, got, words)
+	}
+	positions := map[int]string{0: "Hello", 6: "fri", 10: "nd", 14: "you're", 21: "looking", 29: "good", 34: "today"}
+	if got := StrWordCount(str, 2); !reflect.DeepEqual(got, positions) {
+		t.Errorf("StrWordCount(%q, 2) = %v, want %v", str, got, positions)
+	}
+	if got := StrWordCount(str, 1, "àáãç3"); !reflect.DeepEqual(got, []string{"Hello", "fri3nd", "you're", "looking", "good", "today"}) {
+		t.Errorf("StrWordCount with charlist = %v", got)
+	}
+	if got := StrWordCount("-'a- b-", 1); !reflect.DeepEqual(got, []string{"'a-", "b"}) {
+		t.Errorf("StrWordCount(%q, 1) = %v", "-'a- b-", got)
+	}
+	if got := StrWordCount(str, 3); got != nil {
+		t.Errorf("StrWordCount(%q, 3) = %v, want nil", str, got)
+	}
+}
+
+func TestMbConvertCase(t *testing.T) {
+	tests := []struct {
+		str  string
+		mode int
+		want string
+	}{
+		{"hello wORLD, o'neil", MbCaseTitle, "Hello World, O'neil"},
+		{"straße", MbCaseUpper, "STRASSE"},
+		{"straße", MbCaseUpperSimple, "STRAßE"},
+		{"Straße", MbCaseFold, "strasse"},
+		{"Straße", MbCaseFoldSimple, "straße"},
+		{"ΑΒΓ", MbCaseLower, "αβγ"},
+		{"ﬁsh ßtraße", MbCaseTitle, "Fish Sstraße"},
+		{"ﬁsh", MbCaseTitleSimple, "ﬁsh"},
+		{"ΟΔΟΣ ΣΑ", MbCaseTitle, "Οδο\u03c2 Σα"},
+	}
+	for _, test := range tests {
+		if got := MbConvertCase(test.str, test.mode); got != test.want {
+			t.Errorf("MbConvertCase(%q, %d) = %q, want %q", test.str, test.mode, got, test.want)
+		}
+	}
+}
+
+func TestCaseConverters(t *testing.T) {
+	tests := []struct {
+		str                         string
+		camel, studly, snake, kebab string
+	}{
+		{"user_id", "userId", "UserId", "user_id", "user-id"},
+		{"HTTPServer", "httpServer", "HttpServer", "http_server", "http-server"},
+		{"XMLHttpRequest", "xmlHttpRequest", "XmlHttpRequest", "xml_http_request", "xml-http-request"},
+		{"getHTTPResponse2Code", "getHttpResponse2Code", "GetHttpResponse2Code", "get_http_response2_code", "get-http-response2-code"},
+		{"  hello   world ", "helloWorld", "HelloWorld", "hello_world", "hello-world"},
+		{"userID", "userId", "UserId", "user_id", "user-id"},
+		{"", "", "", "", ""},
+	}
+	for _, test := range tests {
+		if got := CamelCase(test.str); got != test.camel {
+			t.Errorf("CamelCase(%q) = %q, want %q", test.str, got, test.camel)
+		}
+		if got := StudlyCase(test.str); got != test.studly {
+			t.Errorf("StudlyCase(%q) = %q, want %q", test.str, got, test.studly)
+		}
+		if got := SnakeCase(test.str); got != test.snake {
+			t.Errorf("SnakeCase(%q) = %q, want %q", test.str, got, test.snake)
+		}
+		if got := KebabCase(test.str); got != test.kebab {
+			t.Errorf("KebabCase(%q) = %q, want %q", test.str, got, test.kebab)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package php
+
+// The Ctype* functions check the bytes of text against the character classes of the C locale like PHP,
+// they all return false for an empty string.
+
+// CtypeAlnum check for alphanumeric character(s)
+//
+// see http://php.net/manual/en/function.ctype-alnum.php
+func CtypeAlnum(text string) bool {
+	return ctype(text, isAlnum)
+}
+
+// CtypeAlpha check for alphabetic character(s)
+//
+// see http://php.net/manual/en/function.ctype-alpha.php
+func CtypeAlpha(text string) bool {
+	return ctype(text, func(c byte) bool { return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' })
+}
+
+// CtypeCntrl check for control character(s)
+//
+// see http://php.net/manual/en/function.ctype-cntrl.php
+func CtypeCntrl(text string) bool {
+	return ctype(text, func(c byte) bool { return c < 32 || c == 127 })
+}
+
+// CtypeDigit check for numeric character(s)
+// .eg CtypeDigit("1820") returns true, CtypeDigit("10.5") and CtypeDigit("-1") return false
+//
+// see http://php.net/manual/en/function.ctype-digit.php
+func CtypeDigit(text string) bool {
+	return ctype(text, func(c byte) bool { return c >= '0' && c <= '9' })
+}
+
+// CtypeGraph check for any printable character(s) except space
+//
+// see http://php.net/manual/en/function.ctype-graph.php
+func CtypeGraph(text string) bool {
+	return ctype(text, func(c byte) bool { return c > 32 && c < 127 })
+}
+
+// CtypeLower check for lowercase character(s)
+//
+// see http://php.net/manual/en/function.ctype-lower.php
+func CtypeLower(text string) bool {
+	return ctype(text, func(c byte) bool { return c >= 'a' && c <= 'z' })
+}
+
+// CtypePrint check for printable character(s)
+//
+// see http://php.net/manual/en/function.ctype-print.php
+func CtypePrint(text string) bool {
+	return ctype(text, func(c byte) bool { return c >= 32 && c < 127 })
+}
+
+// CtypePunct check for any printable character which is not whitespace or an alphanumeric character
+//
+// see http://php.net/manual/en/function.ctype-punct.php
+func CtypePunct(text string) bool {
+	return ctype(text, func(c byte) bool { return c > 32 && c < 127 && !isAlnum(c) })
+}
+
+// CtypeSpace check for whitespace character(s), which are " \t\n\v\f\r"
+//
+// see http://php.net/manual/en/function.ctype-space.php
+func CtypeSpace(text string) bool {
+	return ctype(text, func(c byte) bool { return c == ' ' || c >= '\t' && c <= '\r' })
+}
+
+// CtypeUpper check for uppercase character(s)
+//
+// see http://php.net/manual/en/function.ctype-upper.php
+func CtypeUpper(text string) bool {
+	return ctype(text, func(c byte) bool { return c >= 'A' && c <= 'Z' })
+}
+
+// CtypeXdigit check for character(s) representing a hexadecimal digit
+//
+// see http://php.net/manual/en/function.ctype-xdigit.php
+func CtypeXdigit(text string) bool {
+	return ctype(text, isHexDigit)
+}
+
+// ctype reports if text is not empty and class is true for all its bytes
+func ctype(text string, class func(c byte) bool) bool {
+	if text == "" {
+		return false
+	}
+	for i := 0; i < len(text); i++ {
+		if !class(text[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,499 @@
+package php
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/ianaindex"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	xunicode "golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/encoding/unicode/utf32"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// defaultDetectOrder is the candidate list of MbDetectEncoding, the same as PHP's mb_detect_order()
+var defaultDetectOrder = []string{"ASCII", "UTF-8"}
+
+// encodings maps the upper-cased names and aliases PHP accepts to the encodings.
+// UTF-8 and ASCII are not in the map, they are handled without a transformer.
+var encodings = map[string]encoding.Encoding{
+	"GBK":          simplifiedchinese.GBK,
+	"CP936":        simplifiedchinese.GBK,
+	"GB2312":       gb2312,
+	"EUC-CN":       gb2312,
+	"GB18030":      simplifiedchinese.GB18030,
+	"HZ":           simplifiedchinese.HZGB2312,
+	"BIG5":         traditionalchinese.Big5,
+	"BIG-5":        traditionalchinese.Big5,
+	"CP950":        traditionalchinese.Big5,
+	"SJIS":         japanese.ShiftJIS,
+	"SHIFT_JIS":    japanese.ShiftJIS,
+	"CP932":        japanese.ShiftJIS,
+	"EUC-JP":       japanese.EUCJP,
+	"ISO-2022-JP":  japanese.ISO2022JP,
+	"EUC-KR":       korean.EUCKR,
+	"UHC":          korean.EUCKR,
+	"CP949":        korean.EUCKR,
+	"UTF-16":       xunicode.UTF16(xunicode.BigEndian, xunicode.ExpectBOM),
+	"UTF-16BE":     xunicode.UTF16(xunicode.BigEndian, xunicode.IgnoreBOM),
+	"UTF-16LE":     xunicode.UTF16(xunicode.LittleEndian, xunicode.IgnoreBOM),
+	"UTF-32":       utf32.UTF32(utf32.BigEndian, utf32.ExpectBOM),
+	"UTF-32BE":     utf32.UTF32(utf32.BigEndian, utf32.IgnoreBOM),
+	"UTF-32LE":     utf32.UTF32(utf32.LittleEndian, utf32.IgnoreBOM),
+	"ISO-8859-1":   charmap.ISO8859_1,
+	"LATIN1":       charmap.ISO8859_1,
+	"ISO-8859-2":   charmap.ISO8859_2,
+	"ISO-8859-5":   charmap.ISO8859_5,
+	"ISO-8859-15":  charmap.ISO8859_15,
+	"WINDOWS-1250": charmap.Windows1250,
+	"CP1250":       charmap.Windows1250,
+	"WINDOWS-1251": charmap.Windows1251,
+	"CP1251":       charmap.Windows1251,
+	"WINDOWS-1252": charmap.Windows1252,
+	"CP1252":       charmap.Windows1252,
+	"KOI8-R":       charmap.KOI8R,
+}
+
+// gb2312 is GB2312 in its EUC-CN form, the subset of GBK whose two bytes are both in 0xA1-0xFE
+var gb2312 encoding.Encoding = gb2312Encoding{}
+
+// errGB2312Unsupported is returned by the encoder of gb2312 for the characters out of GB2312
+var errGB2312Unsupported = errors.New("gb2312: rune not supported by encoding")
+
+// stateful is the encodings which can not be decoded a character at a time
+var stateful = map[string]bool{
+	"HZ":          true,
+	"ISO-2022-JP": true,
+	"UTF-16":      true,
+	"UTF-32":      true,
+}
+
+// translitTable is the replacements of Iconv's //TRANSLIT for characters that
+// can not be reduced by removing their accents
+var translitTable = map[rune]string{
+	'\u00a0': " ",
+	'‘':      "'",
+	'’':      "'",
+	'‚':      ",",
+	'“':      "\"",
+	'”':      "\"",
+	'„':      ",,",
+	'–':      "-",
+	'—':      "-",
+	'…':      "...",
+	'•':      "o",
+	'«':      "<<",
+	'»':      ">>",
+	'©':      "(C)",
+	'®':      "(R)",
+	'™':      "(TM)",
+	'€':      "EUR",
+	'ß':      "ss",
+	'Æ':      "AE",
+	'æ':      "ae",
+	'Œ':      "OE",
+	'œ':      "oe",
+	'Ø':      "O",
+	'ø':      "o",
+	'Đ':      "D",
+	'đ':      "d",
+	'Ł':      "L",
+	'ł':      "l",
+	'Þ':      "TH",
+	'þ':      "th",
+}
+
+// MbConvertEncoding convert str from fromEncoding to toEncoding
+//
+// fromEncoding can be a comma separated list like "GBK,BIG5" or "auto", the first encoding
+// in which str is valid is used. It's the internal encoding UTF-8 if omitted.
+// Invalid byte sequences and the characters which can not be represented in toEncoding
+// are replaced with "?" like PHP's mb_convert_encoding does.
+//
+// see http://php.net/manual/en/function.mb-convert-encoding.php
+func MbConvertEncoding(str, toEncoding string, fromEncoding ...string) (string, error) {
+	from := "UTF-8"
+	if len(fromEncoding) > 0 {
+		from = fromEncoding[0]
+	}
+	if strings.Contains(from, ",") || strings.EqualFold(from, "auto") {
+		list := defaultDetectOrder
+		if !strings.EqualFold(from, "auto") {
+			list = strings.Split(from, ",")
+		}
+		if from = MbDetectEncoding(str, list...); from == "" {
+			return "", errors.New("unable to detect character encoding")
+		}
+	}
+
+	s, err := decodeString(str, from, func(string) (string, error) { return "?", nil })
+	if err != nil {
+		return "", err
+	}
+	return encodeString(s, toEncoding, func(rune) (string, error) { return "?", nil })
+}
+
+// Iconv convert str from inCharset to outCharset
+//
+// outCharset can be appended //TRANSLIT to approximate the characters which can not be represented
+// with similar looking ones, or //IGNORE to drop them, otherwise an error is returned.
+// Invalid byte sequences are dropped with //IGNORE and reported as an error without it.
+//
+// see http://php.net/manual/en/function.iconv.php
+func Iconv(inCharset, outCharset, str string) (string, error) {
+	var translit, ignore bool
+	parts := strings.Split(outCharset, "//")
+	for _, p := range parts[1:] {
+		switch strings.ToUpper(p) {
+		case "TRANSLIT":
+			translit = true
+		case "IGNORE":
+			ignore = true
+		}
+	}
+	outCharset = parts[0]
+	inCharset = strings.Split(inCharset, "//")[0]
+
+	s, err := decodeString(str, inCharset, func(seq string) (string, error) {
+		if ignore {
+			return "", nil
+		}
+		return "", fmt.Errorf("detected an illegal character %q in input string", seq)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	onError := func(r rune) (string, error) {
+		if translit {
+			if t := transliterate(r); t != "" {
+				if s, err := encodeString(t, outCharset, nil); err == nil {
+					return s, nil
+				}
+			}
+			if !ignore {
+				return encodeString("?", outCharset, nil)
+			}
+		}
+		if ignore {
+			return "", nil
+		}
+		return "", fmt.Errorf("detected an illegal character %q in output charset %s", r, outCharset)
+	}
+	return encodeString(s, outCharset, onError)
+}
+
+// MbCheckEncoding checks if str is valid for the specified encoding
+//
+// see http://php.net/manual/en/function.mb-check-encoding.php
+func MbCheckEncoding(str, enc string) bool {
+	_, err := decodeString(str, enc, func(string) (string, error) { return "", errors.New("invalid") })
+	return err == nil
+}
+
+// MbDetectEncoding detect character encoding
+//
+// It returns the first of the candidate encodings in which str is valid, or empty string if there
+// is none. The candidates are "ASCII" and "UTF-8" if omitted. Order the list from the most strict
+// encoding to the least, e.g. "ASCII", "UTF-8", "GB18030", "BIG5", since many byte sequences are
+// valid in more than one legacy encoding.
+//
+// see http://php.net/manual/en/function.mb-detect-encoding.php
+func MbDetectEncoding(str string, candidates ...string) string {
+	if len(candidates) == 0 {
+		candidates = defaultDetectOrder
+	}
+	for _, enc := range candidates {
+		enc = strings.TrimSpace(enc)
+		if MbCheckEncoding(str, enc) {
+			return enc
+		}
+	}
+	return ""
+}
+
+// lookupEncoding finds the encoding by name, nil is returned for UTF-8 and ASCII
+func lookupEncoding(name string) (encoding.Encoding, string, error) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	switch name {
+	case "UTF-8", "UTF8":
+		return nil, "UTF-8", nil
+	case "ASCII", "US-ASCII":
+		return nil, "ASCII", nil
+	}
+	if e, ok := encodings[name]; ok {
+		return e, name, nil
+	}
+	if e, err := ianaindex.IANA.Encoding(name); err == nil && e != nil {
+		return e, name, nil
+	}
+	return nil, name, fmt.Errorf("unknown encoding %q", name)
+}
+
+// decodeString converts str from enc to UTF-8, onInvalid is called with every invalid byte sequence
+// and returns its replacement or an error to abort the conversion
+func decodeString(str, enc string, onInvalid func(seq string) (string, error)) (string, error) {
+	e, name, err := lookupEncoding(enc)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.Grow(len(str))
+	if e == nil {
+		for i := 0; i < len(str); {
+			r, size, ok := decodeRune(str[i:])
+			if ok && (name != "ASCII" || r < utf8.RuneSelf) {
+				b.WriteString(str[i : i+size])
+			} else {
+				if name == "ASCII" {
+					size = 1
+				}
+				s, err := onInvalid(str[i : i+size])
+				if err != nil {
+					return "", err
+				}
+				b.WriteString(s)
+			}
+			i += size
+		}
+		return b.String(), nil
+	}
+
+	out, err := e.NewDecoder().String(str)
+	if err != nil {
+		return "", err
+	}
+	if !strings.ContainsRune(out, utf8.RuneError) {
+		return out, nil
+	}
+
+	// the decoders of x/text replace invalid sequences with U+FFFD, so when there is one
+	// decode a character at a time to tell them from a genuine U+FFFD and find out the bytes
+	if stateful[name] {
+		for _, r := range out {
+			if r != utf8.RuneError {
+				b.WriteRune(r)
+				continue
+			}
+			s, err := onInvalid(string(r))
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(s)
+		}
+		return b.String(), nil
+	}
+	for i := 0; i < len(str); {
+		size := 0
+		for n := 1; n <= 4 && i+n <= len(str); n++ {
+			if s, ok := decodeChar(e, str[i:i+n]); ok {
+				b.WriteString(s)
+				size = n
+				break
+			}
+		}
+		if size == 0 {
+			s, err := onInvalid(str[i : i+1])
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(s)
+			size = 1
+		}
+		i += size
+	}
+	return b.String(), nil
+}
+
+// decodeChar decodes a single character of a stateless encoding, ok is false if seq is invalid or incomplete
+func decodeChar(e encoding.Encoding, seq string) (string, bool) {
+	s, err := e.NewDecoder().String(seq)
+	if err != nil || s == "" {
+		return "", false
+	}
+	if strings.ContainsRune(s, utf8.RuneError) {
+		// only GB18030 and the Unicode encodings can represent U+FFFD itself
+		if enc, err := e.NewEncoder().String(s); err != nil || enc != seq {
+			return "", false
+		}
+	}
+	return s, true
+}
+
+// encodeString converts str from UTF-8 to enc, onUnsupported is called with every character that
+// can not be represented in enc and returns its replacement or an error to abort the conversion
+func encodeString(str, enc string, onUnsupported func(r rune) (string, error)) (string, error) {
+	e, name, err := lookupEncoding(enc)
+	if err != nil {
+		return "", err
+	}
+	if onUnsupported == nil {
+		onUnsupported = func(r rune) (string, error) {
+			return "", fmt.Errorf("character %q can not be represented in %s", r, name)
+		}
+	}
+
+	var b strings.Builder
+	b.Grow(len(str))
+	if e == nil {
+		if name == "UTF-8" {
+			return str, nil
+		}
+		for _, r := range str {
+			if r < utf8.RuneSelf {
+				b.WriteRune(r)
+				continue
+			}
+			s, err := onUnsupported(r)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(s)
+		}
+		return b.String(), nil
+	}
+
+	for str != "" {
+		s, n, err := transform.String(e.NewEncoder(), str)
+		if err == nil {
+			b.WriteString(s)
+			break
+		}
+		// encode the text before the unsupported character with a fresh encoder, so a stateful
+		// encoding like ISO-2022-JP shifts back to ASCII before the replacement
+		if s, _, err = transform.String(e.NewEncoder(), str[:n]); err != nil {
+			return "", err
+		}
+		b.WriteString(s)
+		r, size := utf8.DecodeRuneInString(str[n:])
+		if size == 0 {
+			break
+		}
+		s, err = onUnsupported(r)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(s)
+		str = str[n+size:]
+	}
+	return b.String(), nil
+}
+
+// transliterate approximates r with ASCII characters, it returns empty string if there is no approximation
+func transliterate(r rune) string {
+	if s, ok := translitTable[r]; ok {
+		return s
+	}
+	var b strings.Builder
+	for _, c := range norm.NFD.String(string(r)) {
+		if !unicode.Is(unicode.Mn, c) {
+			b.WriteRune(c)
+		}
+	}
+	if s := b.String(); s != string(r) {
+		return s
+	}
+	return ""
+}
+
+type gb2312Encoding struct{}
+
+func (gb2312Encoding) NewDecoder() *encoding.Decoder {
+	return &encoding.Decoder{Transformer: &gb2312Decoder{gbk: simplifiedchinese.GBK.NewDecoder()}}
+}
+
+func (gb2312Encoding) NewEncoder() *encoding.Encoder {
+	return &encoding.Encoder{Transformer: &gb2312Encoder{gbk: simplifiedchinese.GBK.NewEncoder()}}
+}
+
+// isGB2312Byte reports if c can be a byte of a two-byte character of GB2312
+func isGB2312Byte(c byte) bool {
+	return c >= 0xA1 && c <= 0xFE
+}
+
+// gb2312Decoder decodes the ASCII bytes and the two-byte characters in 0xA1A1-0xFEFE with GBK,
+// the other bytes are invalid and decoded to U+FFFD like the decoders of x/text
+type gb2312Decoder struct {
+	transform.NopResetter
+	gbk *encoding.Decoder
+}
+
+func (d *gb2312Decoder) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	for nSrc < len(src) {
+		c := src[nSrc]
+		switch {
+		case c < utf8.RuneSelf:
+			if nDst >= len(dst) {
+				return nDst, nSrc, transform.ErrShortDst
+			}
+			dst[nDst] = c
+			nDst++
+			nSrc++
+			continue
+		case isGB2312Byte(c) && nSrc+1 >= len(src) && !atEOF:
+			return nDst, nSrc, transform.ErrShortSrc
+		case isGB2312Byte(c) && nSrc+1 < len(src) && isGB2312Byte(src[nSrc+1]):
+			n, _, err := d.gbk.Transform(dst[nDst:], src[nSrc:nSrc+2], true)
+			if err != nil {
+				return nDst, nSrc, err
+			}
+			nDst += n
+			nSrc += 2
+			continue
+		}
+		if nDst+utf8.UTFMax > len(dst) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		nDst += utf8.EncodeRune(dst[nDst:], utf8.RuneError)
+		nSrc++
+	}
+	return nDst, nSrc, nil
+}
+
+// gb2312Encoder encodes with GBK and rejects the characters whose bytes are out of GB2312
+type gb2312Encoder struct {
+	transform.NopResetter
+	gbk *encoding.Encoder
+}
+
+func (e *gb2312Encoder) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	var buf [4]byte
+	for nSrc < len(src) {
+		if src[nSrc] < utf8.RuneSelf {
+			if nDst >= len(dst) {
+				return nDst, nSrc, transform.ErrShortDst
+			}
+			dst[nDst] = src[nSrc]
+			nDst++
+			nSrc++
+			continue
+		}
+		if !atEOF && !utf8.FullRune(src[nSrc:]) {
+			return nDst, nSrc, transform.ErrShortSrc
+		}
+		r, size := utf8.DecodeRune(src[nSrc:])
+		if r == utf8.RuneError && size == 1 {
+			return nDst, nSrc, encoding.ErrInvalidUTF8
+		}
+		n, _, err := e.gbk.Transform(buf[:], src[nSrc:nSrc+size], true)
+		if err != nil || n != 2 || !isGB2312Byte(buf[0]) || !isGB2312Byte(buf[1]) {
+			return nDst, nSrc, errGB2312Unsupported
+		}
+		if nDst+2 > len(dst) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		nDst += copy(dst[nDst:], buf[:2])
+		nSrc += size
+	}
+	return nDst, nSrc, nil
+}
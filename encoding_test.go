@@ -0,0 +1,55 @@
+package php
+
+import "testing"
+
+func TestGB2312(t *testing.T) {
+	tests := []struct {
+		str    string
+		gb2312 bool
+		gbk    bool
+	}{
+		{"abc", true, true},
+		{"\xd6\xd0\xce\xc4", true, true}, // 中文
+		{"\x81\x40", false, true},        // 丂, GBK only
+		{"\xd6\xd0\x81\x40", false, true},
+		{"\xd6", false, false},
+		{"\xd6\x41", false, true},
+	}
+	for _, test := range tests {
+		if got := MbCheckEncoding(test.str, "GB2312"); got != test.gb2312 {
+			t.Errorf("MbCheckEncoding(%q, GB2312) = %v, want %v", test.str, got, test.gb2312)
+		}
+		if got := MbCheckEncoding(test.str, "GBK"); got != test.gbk {
+			t.Errorf("MbCheckEncoding(%q, GBK) = %v, want %v", test.str, got, test.gbk)
+		}
+	}
+	if got := MbDetectEncoding("\x81\x40", "GB2312", "GBK"); got != "GBK" {
+		t.Errorf("MbDetectEncoding = %q, want GBK", got)
+	}
+	if got, _ := MbConvertEncoding("中文丂a", "EUC-CN"); got != "\xd6\xd0\xce\xc4?a" {
+		t.Errorf("MbConvertEncoding = %q", got)
+	}
+	if got, _ := MbConvertEncoding("\xd6\xd0\x81\x40a", "UTF-8", "GB2312"); got != "中?@a" {
+		t.Errorf("MbConvertEncoding = %q", got)
+	}
+}
+
+func TestMbConvertEncodingStateful(t *testing.T) {
+	tests := []struct {
+		str, want string
+	}{
+		{"日本語", "\x1b$BF|K\\8l\x1b(B"},
+		{"日本€語", "\x1b$BF|K\\\x1b(B?\x1b$B8l\x1b(B"},
+		{"€日本", "?\x1b$BF|K\\\x1b(B"},
+		{"日本€", "\x1b$BF|K\\\x1b(B?"},
+		{"a€b", "a?b"},
+	}
+	for _, test := range tests {
+		if got, err := MbConvertEncoding(test.str, "ISO-2022-JP", "UTF-8"); err != nil || got != test.want {
+			t.Errorf("MbConvertEncoding(%q, ISO-2022-JP) = %q, %v, want %q", test.str, got, err, test.want)
+		}
+	}
+	if got, err := Iconv("UTF-8", "ISO-2022-JP//TRANSLIT", "日本é語"); err != nil || got != "\x1b$BF|K\\\x1b(Be\x1b$B8l\x1b(B" {
+		t.Errorf("Iconv(ISO-2022-JP//TRANSLIT) = %q, %v", got, err)
+	}
+}
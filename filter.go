@@ -0,0 +1,150 @@
+package php
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// WordFilter finds and masks the words of a list, like sensitive words, in texts
+//
+// It's built once from the list and safe for concurrent use. Words are matched case-insensitively
+// with the same Unicode case folding as Ireplace, and at every position the longest word wins.
+type WordFilter struct {
+	trie  *trie
+	words []string
+	noise map[rune]bool // folded noise characters
+}
+
+// WordMatch is a word found by WordFilter
+type WordMatch struct {
+	Word   string // the word in the list
+	Text   string // the matched text including the noise characters
+	Offset int    // offset of the match in runes
+	Length int    // length of the match in runes
+}
+
+// NewWordFilter builds the WordFilter of words
+//
+// The optional noise is a list of characters like " *-_." which are ignored between
+// the letters of a word, so "b-a d" is found as "bad". Empty words are ignored.
+// .eg NewWordFilter([]string{"敏感词", "bad"}, " *-")
+func NewWordFilter(words []string, noise ...string) *WordFilter {
+	f := &WordFilter{
+		trie:  newTrie(),
+		noise: make(map[rune]bool),
+	}
+	if len(noise) > 0 {
+		for _, r := range noise[0] {
+			f.noise[foldRune(r)] = true
+		}
+	}
+
+	var key []byte
+	for _, w := range words {
+		key = key[:0]
+		for _, r := range foldString(w) {
+			if !f.noise[r] {
+				key = utf8.AppendRune(key, r)
+			}
+		}
+		if len(key) == 0 {
+			continue
+		}
+		f.trie.insert(string(key), len(f.words))
+		f.words = append(f.words, w)
+	}
+	return f
+}
+
+// Find returns every match of the words in text from left to right, matches do not overlap
+func (f *WordFilter) Find(text string) []WordMatch {
+	var res []WordMatch
+	f.scan(text, func(m WordMatch) bool {
+		res = append(res, m)
+		return true
+	})
+	return res
+}
+
+// Contains checks if there is any word of the list in text
+func (f *WordFilter) Contains(text string) bool {
+	found := false
+	f.scan(text, func(WordMatch) bool {
+		found = true
+		return false
+	})
+	return found
+}
+
+// Mask replaces every rune of the matched text with mask
+// .eg NewWordFilter([]string{"bad"}).Mask("so bad", '*') returns "so ***"
+func (f *WordFilter) Mask(text string, mask rune) string {
+	var b strings.Builder
+	last := 0
+	f.scanBytes(text, func(m WordMatch, start, end int) bool {
+		b.WriteString(text[last:start])
+		b.WriteString(strings.Repeat(string(mask), m.Length))
+		last = end
+		return true
+	})
+	if last == 0 {
+		return text
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// scan calls fn with every match until it returns false
+func (f *WordFilter) scan(text string, fn func(m WordMatch) bool) {
+	f.scanBytes(text, func(m WordMatch, start, end int) bool {
+		return fn(m)
+	})
+}
+
+// scanBytes calls fn with every match and its byte range in text until it returns false
+func (f *WordFilter) scanBytes(text string, fn func(m WordMatch, start, end int) bool) {
+	var buf [utf8.UTFMax]byte
+	offset := 0
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+
+		// walk the trie from i, the longest word wins
+		value, end, runes := -1, 0, 0
+		if !f.noise[foldRune(r)] {
+			n := int32(0)
+			for j, k := i, 0; j < len(text) && n >= 0; k++ {
+				c, l := utf8.DecodeRuneInString(text[j:])
+				j += l
+				c = foldRune(c)
+				if k > 0 && f.noise[c] {
+					continue
+				}
+				for _, b := range buf[:utf8.EncodeRune(buf[:], c)] {
+					if n = f.trie.step(n, b); n < 0 {
+						break
+					}
+				}
+				if n >= 0 && f.trie.nodes[n].value >= 0 {
+					value, end, runes = int(f.trie.nodes[n].value), j, k+1
+				}
+			}
+		}
+
+		if value < 0 {
+			i += size
+			offset++
+			continue
+		}
+		m := WordMatch{
+			Word:   f.words[value],
+			Text:   text[i:end],
+			Offset: offset,
+			Length: runes,
+		}
+		if !fn(m, i, end) {
+			return
+		}
+		i = end
+		offset += runes
+	}
+}
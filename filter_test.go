@@ -0,0 +1,25 @@
+package php
+
+import "testing"
+
+func TestWordFilterNoiseFolded(t *testing.T) {
+	f := NewWordFilter([]string{"bxad", "good"}, "X")
+	tests := []struct {
+		text, want string
+	}{
+		{"so bad", "so ***"},
+		{"so bXad", "so ****"},
+		{"so bxad", "so ****"},
+		{"so bXXad", "so *****"},
+		{"xbad", "x***"},
+		{"gXoOd", "*****"},
+	}
+	for _, test := range tests {
+		if got := f.Mask(test.text, '*'); got != test.want {
+			t.Errorf("Mask(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+	if f.Contains("bxxd") {
+		t.Error(`Contains("bxxd") = true`)
+	}
+}
@@ -0,0 +1,701 @@
+package php
+
+import (
+	"fmt"
+	"net/netip"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Filters of FilterVar, the same as PHP's FILTER_* constants
+const (
+	// FilterValidateInt validates an int, the options are "min_range" and "max_range"
+	FilterValidateInt int = 257
+	// FilterValidateBool returns true for "1", "true", "on" and "yes", false for "0", "false", "off", "no" and ""
+	FilterValidateBool int = 258
+	// FilterValidateBoolean is an alias of FilterValidateBool
+	FilterValidateBoolean int = 258
+	// FilterValidateFloat validates a float, the options are "decimal", "thousand", "min_range" and "max_range"
+	FilterValidateFloat int = 259
+	// FilterValidateRegexp validates the value against the PHP pattern of the option "regexp"
+	FilterValidateRegexp int = 272
+	// FilterValidateURL validates a URL
+	FilterValidateURL int = 273
+	// FilterValidateEmail validates an e-mail address
+	FilterValidateEmail int = 274
+	// FilterValidateIP validates an IPv4 or IPv6 address
+	FilterValidateIP int = 275
+	// FilterValidateMAC validates a MAC address, the option "separator" restricts the separator
+	FilterValidateMAC int = 276
+	// FilterValidateDomain validates a domain name
+	FilterValidateDomain int = 277
+
+	// FilterUnsafeRaw does nothing, optionally strips or encodes special characters
+	FilterUnsafeRaw int = 516
+	// FilterDefault is the same as FilterUnsafeRaw
+	FilterDefault int = 516
+	// FilterSanitizeString strips tags and encodes quotes, it's deprecated in PHP
+	FilterSanitizeString int = 513
+	// FilterSanitizeStripped is an alias of FilterSanitizeString
+	FilterSanitizeStripped int = 513
+	// FilterSanitizeEncoded URL-encodes the string
+	FilterSanitizeEncoded int = 514
+	// FilterSanitizeSpecialChars HTML-encodes '"<>& and the characters with ASCII value less than 32
+	FilterSanitizeSpecialChars int = 515
+	// FilterSanitizeFullSpecialChars is the same as Htmlentities with EntQuotes
+	FilterSanitizeFullSpecialChars int = 522
+	// FilterSanitizeEmail removes all characters except letters, digits and !#$%&'*+-=?^_`{|}~@.[]
+	FilterSanitizeEmail int = 517
+	// FilterSanitizeURL removes all characters except letters, digits and $-_.+!*'(),{}|\^~[]`<>#%";/?:@&=
+	FilterSanitizeURL int = 518
+	// FilterSanitizeNumberInt removes all characters except digits, plus and minus sign
+	FilterSanitizeNumberInt int = 519
+	// FilterSanitizeNumberFloat removes all characters except digits, +- and optionally .,eE
+	FilterSanitizeNumberFloat int = 520
+	// FilterSanitizeAddSlashes is the same as Addslashes
+	FilterSanitizeAddSlashes int = 523
+	// FilterCallback calls the func(string) interface{} of the option "callback"
+	FilterCallback int = 1024
+)
+
+// Flags of FilterVar, the same as PHP's FILTER_FLAG_* constants
+const (
+	FilterFlagNone            int = 0
+	FilterFlagAllowOctal      int = 1
+	FilterFlagAllowHex        int = 2
+	FilterFlagStripLow        int = 4
+	FilterFlagStripHigh       int = 8
+	FilterFlagEncodeLow       int = 16
+	FilterFlagEncodeHigh      int = 32
+	FilterFlagEncodeAmp       int = 64
+	FilterFlagNoEncodeQuotes  int = 128
+	FilterFlagEmptyStringNull int = 256
+	FilterFlagStripBacktick   int = 512
+	FilterFlagAllowFraction   int = 4096
+	FilterFlagAllowThousand   int = 8192
+	FilterFlagAllowScientific int = 16384
+	FilterFlagPathRequired    int = 262144
+	FilterFlagQueryRequired   int = 524288
+	FilterFlagIPv4            int = 1048576
+	FilterFlagIPv6            int = 2097152
+	FilterFlagNoResRange      int = 4194304
+	FilterFlagNoPrivRange     int = 8388608
+	FilterFlagGlobalRange     int = 268435456
+	FilterFlagHostname        int = 1048576
+	FilterFlagEmailUnicode    int = 1048576
+	FilterNullOnFailure       int = 134217728
+)
+
+// the characters allowed besides ASCII letters and digits
+const (
+	filterURLChars      = "$-_.+!*'(),{}|\\^~[]`<>#%\";/?:@&="
+	filterEmailChars    = "!#$%&'*+-=?^_`{|}~@.[]"
+	filterEmailAtext    = "!#$%&'*+-/=?^_`{|}~"
+	filterUserinfoChars = "-._~!$&'()*+,;=:"
+)
+
+var (
+	filterEmailDomain = regexp.MustCompile(`^(?i)(?:(?:xn--)?[a-z0-9]+(?:-+[a-z0-9]+)*\.)+(?:[a-z][a-z0-9]*|xn--[a-z0-9]+)(?:-+[a-z0-9]+)*$`)
+
+	filterPrivRanges   = filterPrefixes("10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "fc00::/7")
+	filterResRanges    = filterPrefixes("0.0.0.0/8", "127.0.0.0/8", "169.254.0.0/16", "240.0.0.0/4", "::/128", "::1/128", "::ffff:0:0/96", "fe80::/10")
+	filterGlobalRanges = filterPrefixes("100.64.0.0/10", "192.0.0.0/24", "192.0.2.0/24", "198.18.0.0/15", "198.51.100.0/24",
+		"203.0.113.0/24", "100::/64", "2001::/23", "2001:2::/48", "2001:db8::/32", "2001:10::/28")
+)
+
+// FilterVar filters a variable with a specified filter
+//
+// value is converted to string like PHP, slices, maps and structs always fail. options holds the options
+// of the filter like "min_range", "regexp" or "callback" and the Filter* flags under the key "flags", the
+// option "default" is the value returned when the validation fails.
+//
+// It returns the filtered value and true, an int for FilterValidateInt, a float64 for FilterValidateFloat,
+// a bool for FilterValidateBool and a string for the others. When the validation fails it returns false
+// like PHP, or nil with FilterNullOnFailure, or the default option, with ok false.
+// .eg FilterVar("42", FilterValidateInt, map[string]interface{}{"min_range": 1}) returns 42, true
+// FilterVar("0x1A", FilterValidateInt, map[string]interface{}{"flags": FilterFlagAllowHex}) returns 26, true
+// FilterVar("off", FilterValidateBool) returns false, true and FilterVar("maybe", FilterValidateBool) returns false, false
+// FilterVar("10.0.0.1", FilterValidateIP, map[string]interface{}{"flags": FilterFlagNoPrivRange}) returns false, false
+//
+// see http://php.net/manual/en/function.filter-var.php
+func FilterVar(value interface{}, filter int, options ...map[string]interface{}) (interface{}, bool) {
+	opts := map[string]interface{}{}
+	if len(options) > 0 && options[0] != nil {
+		opts = options[0]
+	}
+	flags := toInt(opts["flags"])
+
+	var res interface{}
+	str, ok := filterScalar(value)
+	if ok {
+		res, ok = filterString(str, filter, flags, opts)
+	}
+	if ok {
+		return res, true
+	}
+	if def, has := opts["default"]; has {
+		return def, false
+	}
+	if flags&FilterNullOnFailure != 0 {
+		return nil, false
+	}
+	return false, false
+}
+
+// filterScalar converts the scalar value to string, ok is false for the other types
+func filterScalar(value interface{}) (string, bool) {
+	switch value.(type) {
+	case nil, string, []byte, fmt.Stringer:
+		return toString(value), true
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return toString(value), true
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return "", true
+		}
+		return filterScalar(rv.Elem().Interface())
+	}
+	return "", false
+}
+
+// filterString applies filter to str
+func filterString(str string, filter, flags int, opts map[string]interface{}) (interface{}, bool) {
+	switch filter {
+	case FilterValidateInt:
+		return filterInt(str, flags, opts)
+	case FilterValidateBool:
+		return filterBool(str)
+	case FilterValidateFloat:
+		return filterFloat(str, flags, opts)
+	case FilterValidateRegexp:
+		pattern, ok := opts["regexp"].(string)
+		if !ok {
+			return nil, false
+		}
+		m, err := PregMatch(pattern, str)
+		return str, m != nil && err == nil
+	case FilterValidateURL:
+		return str, filterURL(str, flags)
+	case FilterValidateEmail:
+		return str, filterEmail(str, flags)
+	case FilterValidateIP:
+		return str, filterIP(str, flags)
+	case FilterValidateMAC:
+		return str, filterMAC(str, opts)
+	case FilterValidateDomain:
+		return str, filterDomain(str, flags&FilterFlagHostname != 0)
+
+	case FilterUnsafeRaw:
+		if flags != 0 && str != "" {
+			return filterEncodeHTML(filterStrip(str, flags), func(c byte) bool {
+				return c == '&' && flags&FilterFlagEncodeAmp != 0 ||
+					c < 32 && flags&FilterFlagEncodeLow != 0 || c >= 127 && flags&FilterFlagEncodeHigh != 0
+			}), true
+		}
+		if str == "" && flags&FilterFlagEmptyStringNull != 0 {
+			return nil, true
+		}
+		return str, true
+	case FilterSanitizeString:
+		str = filterEncodeHTML(filterStrip(str, flags), func(c byte) bool {
+			return (c == '\'' || c == '"') && flags&FilterFlagNoEncodeQuotes == 0 || c == '&' && flags&FilterFlagEncodeAmp != 0 ||
+				c < 32 && flags&FilterFlagEncodeLow != 0 || c >= 127 && flags&FilterFlagEncodeHigh != 0
+		})
+		if str = StripTags(str); str == "" && flags&FilterFlagEmptyStringNull != 0 {
+			return nil, true
+		}
+		return str, true
+	case FilterSanitizeEncoded:
+		str = filterStrip(str, flags)
+		var b strings.Builder
+		for i := 0; i < len(str); i++ {
+			if c := str[i]; isAlnum(c) || c == '-' || c == '.' || c == '_' {
+				b.WriteByte(c)
+			} else {
+				fmt.Fprintf(&b, "%%%02X", c)
+			}
+		}
+		return b.String(), true
+	case FilterSanitizeSpecialChars:
+		return filterEncodeHTML(filterStrip(str, flags), func(c byte) bool {
+			return strings.IndexByte("'\"<>&", c) >= 0 || c < 32 || c >= 127 && flags&FilterFlagEncodeHigh != 0
+		}), true
+	case FilterSanitizeFullSpecialChars:
+		quotes := EntQuotes
+		if flags&FilterFlagNoEncodeQuotes != 0 {
+			quotes = EntNoQuotes
+		}
+		return Htmlentities(str, quotes, false), true
+	case FilterSanitizeEmail:
+		return filterKeep(str, filterEmailChars, true), true
+	case FilterSanitizeURL:
+		return filterKeep(str, filterURLChars, true), true
+	case FilterSanitizeNumberInt:
+		return filterKeep(str, "+-", false), true
+	case FilterSanitizeNumberFloat:
+		allowed := "+-"
+		if flags&FilterFlagAllowFraction != 0 {
+			allowed += "."
+		}
+		if flags&FilterFlagAllowThousand != 0 {
+			allowed += ","
+		}
+		if flags&FilterFlagAllowScientific != 0 {
+			allowed += "eE"
+		}
+		return filterKeep(str, allowed, false), true
+	case FilterSanitizeAddSlashes:
+		return Addslashes(str), true
+	case FilterCallback:
+		callback, ok := opts["callback"].(func(string) interface{})
+		if !ok {
+			return nil, false
+		}
+		return callback(str), true
+	}
+	return nil, false
+}
+
+// filterTrim strips the whitespace PHP's validation filters ignore
+func filterTrim(str string) string {
+	return strings.Trim(str, " \t\r\v\n")
+}
+
+// filterInt is the port of PHP's php_filter_int
+func filterInt(str string, flags int, opts map[string]interface{}) (interface{}, bool) {
+	s := filterTrim(str)
+	var n int
+	var ok bool
+	switch {
+	case s == "0":
+		n, ok = 0, true
+	case flags&FilterFlagAllowHex != 0 && len(s) > 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X'):
+		n, ok = filterParseUint(s[2:], 16)
+	case flags&FilterFlagAllowOctal != 0 && len(s) > 1 && s[0] == '0':
+		s = s[1:]
+		if s[0] == 'o' || s[0] == 'O' {
+			s = s[1:]
+		}
+		n, ok = filterParseUint(s, 8)
+	case len(s) > 0 && s[0] == '0':
+		ok = false
+	default:
+		n, ok = filterParseInt(s)
+	}
+	if !ok {
+		return nil, false
+	}
+	if min, has := opts["min_range"]; has && n < toInt(min) {
+		return nil, false
+	}
+	if max, has := opts["max_range"]; has && n > toInt(max) {
+		return nil, false
+	}
+	return n, true
+}
+
+// filterParseInt parses a decimal int without leading zeros, +0 and -0 are allowed
+func filterParseInt(s string) (int, bool) {
+	digits := strings.TrimLeft(s, "+-")
+	if len(s)-len(digits) > 1 || digits == "" || digits[0] == '0' && digits != "0" {
+		return 0, false
+	}
+	for i := 0; i < len(digits); i++ {
+		if digits[i] < '0' || digits[i] > '9' {
+			return 0, false
+		}
+	}
+	n, err := strconv.Atoi(s)
+	return n, err == nil
+}
+
+// filterParseUint parses the hex or octal digits of s, the values above the maximum int wrap around
+// like PHP and the ones above the maximum uint64 fail
+func filterParseUint(s string, base int) (int, bool) {
+	n, err := strconv.ParseUint(s, base, 64)
+	return int(n), err == nil
+}
+
+// filterBool is the port of PHP's php_filter_boolean
+func filterBool(str string) (interface{}, bool) {
+	switch strings.ToLower(filterTrim(str)) {
+	case "1", "true", "on", "yes":
+		return true, true
+	case "0", "false", "off", "no", "":
+		return false, true
+	}
+	return nil, false
+}
+
+// filterFloat is the port of PHP's php_filter_float
+func filterFloat(str string, flags int, opts map[string]interface{}) (interface{}, bool) {
+	s := filterTrim(str)
+	decSep := byte('.')
+	if v, has := opts["decimal"]; has {
+		d := toString(v)
+		if len(d) != 1 {
+			return nil, false
+		}
+		decSep = d[0]
+	}
+	tsdSep := "'.,"
+	if v, has := opts["thousand"]; has {
+		if tsdSep = toString(v); tsdSep == "" {
+			return nil, false
+		}
+	}
+
+	num := make([]byte, 0, len(s))
+	i := 0
+	if i < len(s) && (s[i] == '+' || s[i] == '-') {
+		num = append(num, s[i])
+		i++
+	}
+	digits := func() int {
+		n := 0
+		for ; i < len(s) && s[i] >= '0' && s[i] <= '9'; i++ {
+			num = append(num, s[i])
+			n++
+		}
+		return n
+	}
+	for first := true; ; first = false {
+		n := digits()
+		if i == len(s) || s[i] == decSep || s[i] == 'e' || s[i] == 'E' {
+			if !first && n != 3 {
+				return nil, false
+			}
+			if i < len(s) && s[i] == decSep {
+				num = append(num, '.')
+				i++
+				digits()
+			}
+			if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+				num = append(num, s[i])
+				i++
+				if i < len(s) && (s[i] == '+' || s[i] == '-') {
+					num = append(num, s[i])
+					i++
+				}
+				digits()
+			}
+			break
+		}
+		if flags&FilterFlagAllowThousand == 0 || strings.IndexByte(tsdSep, s[i]) < 0 ||
+			first && (n < 1 || n > 3) || !first && n != 3 {
+			return nil, false
+		}
+		i++
+	}
+	if i != len(s) {
+		return nil, false
+	}
+
+	f, err := strconv.ParseFloat(string(num), 64)
+	// underflows and overflows fail
+	if err != nil || f == 0 && strings.ContainsAny(string(num), "123456789") {
+		return nil, false
+	}
+	if min, has := opts["min_range"]; has && f < toFloat(min) {
+		return nil, false
+	}
+	if max, has := opts["max_range"]; has && f > toFloat(max) {
+		return nil, false
+	}
+	return f, true
+}
+
+// filterDomain is the port of PHP's _php_filter_validate_domain
+func filterDomain(domain string, hostname bool) bool {
+	if strings.HasPrefix(domain, ".") {
+		return false
+	}
+	// ignore the trailing dot, the length can not exceed 253 characters
+	domain = strings.TrimSuffix(domain, ".")
+	if len(domain) > 253 || hostname && (domain == "" || !isAlnum(domain[0])) {
+		return false
+	}
+	label := 0
+	for i := 0; i < len(domain); i++ {
+		c := domain[i]
+		if c == '.' {
+			// the first and the last characters of a label must be alphanumeric
+			if i+1 == len(domain) || domain[i+1] == '.' || hostname && (!isAlnum(domain[i-1]) || !isAlnum(domain[i+1])) {
+				return false
+			}
+			label = 0
+			continue
+		}
+		if label++; label > 63 || hostname && c != '-' && !isAlnum(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// filterURL is the port of PHP's php_filter_validate_url
+func filterURL(str string, flags int) bool {
+	if filterKeep(str, filterURLChars, true) != str {
+		return false
+	}
+	u, err := ParseUrl(str)
+	if err != nil {
+		return false
+	}
+	scheme, hasScheme := u["scheme"].(string)
+	host, hasHost := u["host"].(string)
+	if hasScheme && (strings.EqualFold(scheme, "http") || strings.EqualFold(scheme, "https")) {
+		if !hasHost {
+			return false
+		}
+		if len(host) > 2 && host[0] == '[' && host[len(host)-1] == ']' && filterIP(host[1:len(host)-1], FilterFlagIPv6) {
+			return true
+		}
+		if !filterDomain(host, true) {
+			return false
+		}
+	}
+	if !hasScheme || !hasHost && scheme != "mailto" && scheme != "news" && scheme != "file" {
+		return false
+	}
+	if _, has := u["path"]; !has && flags&FilterFlagPathRequired != 0 {
+		return false
+	}
+	if _, has := u["query"]; !has && flags&FilterFlagQueryRequired != 0 {
+		return false
+	}
+	for _, key := range []string{"user", "pass"} {
+		if userinfo, has := u[key].(string); has && !filterUserinfo(userinfo) {
+			return false
+		}
+	}
+	return true
+}
+
+// filterUserinfo is the port of PHP's is_userinfo_valid
+func filterUserinfo(str string) bool {
+	for i := 0; i < len(str); {
+		switch c := str[i]; {
+		case isAlnum(c) || strings.IndexByte(filterUserinfoChars, c) >= 0:
+			i++
+		case c == '%' && i+2 < len(str) && str[i+1] >= '0' && str[i+1] <= '9' && isHexDigit(str[i+2]):
+			i += 3
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// filterEmail validates an address like the regular expression of PHP's php_filter_validate_email,
+// which requires a dot in the domain
+func filterEmail(str string, flags int) bool {
+	at := strings.LastIndexByte(str, '@')
+	if len(str) > 320 || at < 0 {
+		return false
+	}
+	local, domain := str[:at], str[at+1:]
+	if emailUnits(str) > 254 || emailUnits(local) > 64 ||
+		!emailLocalPart(local, flags&FilterFlagEmailUnicode != 0) {
+		return false
+	}
+
+	if len(domain) > 2 && domain[0] == '[' && domain[len(domain)-1] == ']' {
+		literal := domain[1 : len(domain)-1]
+		if len(literal) > 5 && strings.EqualFold(literal[:5], "IPv6:") {
+			return filterIP(literal[5:], FilterFlagIPv6)
+		}
+		return filterIP(literal, FilterFlagIPv4)
+	}
+	for _, label := range strings.Split(domain, ".") {
+		if len(label) > 63 {
+			return false
+		}
+	}
+	return filterEmailDomain.MatchString(domain)
+}
+
+// emailUnits counts the characters of an address like the length checks of PHP's regular expression,
+// the quotes are not counted and a quoted pair is one character
+func emailUnits(str string) int {
+	n := 0
+	for i := 0; i < len(str); i++ {
+		switch str[i] {
+		case '"':
+			continue
+		case '\\':
+			i++
+		}
+		n++
+	}
+	return n
+}
+
+// emailLocalPart validates the dot separated atoms and quoted strings of the local part,
+// allowUnicode allows letters and numbers other than ASCII in the atoms
+func emailLocalPart(local string, allowUnicode bool) bool {
+	for i := 0; ; i++ {
+		if i < len(local) && local[i] == '"' {
+			for i++; i < len(local) && local[i] != '"'; i++ {
+				c := local[i]
+				if c == '\\' && i+1 < len(local) && local[i+1] < 0x80 {
+					i++
+				} else if c == 0 || c == '\t' || c == '\n' || c == '\r' || c == ' ' || c == '\\' || c >= 0x80 {
+					return false
+				}
+			}
+			if i == len(local) {
+				return false
+			}
+			i++
+		} else {
+			start := i
+			for i < len(local) {
+				r, size := utf8.DecodeRuneInString(local[i:])
+				if r < utf8.RuneSelf && !isAlnum(byte(r)) && strings.IndexByte(filterEmailAtext, byte(r)) < 0 ||
+					r >= utf8.RuneSelf && (!allowUnicode || r == utf8.RuneError || !unicode.IsLetter(r) && !unicode.IsNumber(r)) {
+					break
+				}
+				i += size
+			}
+			if i == start {
+				return false
+			}
+		}
+		if i == len(local) {
+			return true
+		}
+		if local[i] != '.' {
+			return false
+		}
+	}
+}
+
+// filterIP validates an IP address with the IP flags like PHP's php_filter_validate_ip
+func filterIP(str string, flags int) bool {
+	var ipv6 bool
+	switch {
+	case strings.IndexByte(str, ':') >= 0:
+		ipv6 = true
+	case strings.IndexByte(str, '.') >= 0:
+	default:
+		return false
+	}
+	if flags&(FilterFlagIPv4|FilterFlagIPv6) == FilterFlagIPv4 && ipv6 ||
+		flags&(FilterFlagIPv4|FilterFlagIPv6) == FilterFlagIPv6 && !ipv6 {
+		return false
+	}
+	ip, err := netip.ParseAddr(str)
+	if err != nil || ip.Zone() != "" || ip.Is4() == ipv6 {
+		return false
+	}
+	inRanges := func(ranges []netip.Prefix) bool {
+		for _, prefix := range ranges {
+			if prefix.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+	global := flags&FilterFlagGlobalRange != 0
+	return !((flags&FilterFlagNoPrivRange != 0 || global) && inRanges(filterPrivRanges) ||
+		(flags&FilterFlagNoResRange != 0 || global) && inRanges(filterResRanges) ||
+		global && inRanges(filterGlobalRanges))
+}
+
+// filterPrefixes parses the CIDR ranges
+func filterPrefixes(cidrs ...string) []netip.Prefix {
+	prefixes := make([]netip.Prefix, len(cidrs))
+	for i, cidr := range cidrs {
+		prefixes[i] = netip.MustParsePrefix(cidr)
+	}
+	return prefixes
+}
+
+// filterMAC is the port of PHP's php_filter_validate_mac
+func filterMAC(str string, opts map[string]interface{}) bool {
+	var tokens, length int
+	var sep byte
+	switch {
+	case len(str) == 14:
+		tokens, length, sep = 3, 4, '.'
+	case len(str) == 17 && (str[2] == '-' || str[2] == ':'):
+		tokens, length, sep = 6, 2, str[2]
+	default:
+		return false
+	}
+	if v, has := opts["separator"]; has {
+		if s := toString(v); len(s) != 1 || s[0] != sep {
+			return false
+		}
+	}
+	for i := 0; i < tokens; i++ {
+		offset := i * (length + 1)
+		if i < tokens-1 && str[offset+length] != sep {
+			return false
+		}
+		for j := offset; j < offset+length; j++ {
+			if !isHexDigit(str[j]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// filterStrip removes the characters of the strip flags
+func filterStrip(str string, flags int) string {
+	if flags&(FilterFlagStripLow|FilterFlagStripHigh|FilterFlagStripBacktick) == 0 {
+		return str
+	}
+	b := make([]byte, 0, len(str))
+	for i := 0; i < len(str); i++ {
+		c := str[i]
+		if c < 32 && flags&FilterFlagStripLow != 0 || c > 127 && flags&FilterFlagStripHigh != 0 ||
+			c == '`' && flags&FilterFlagStripBacktick != 0 {
+			continue
+		}
+		b = append(b, c)
+	}
+	return string(b)
+}
+
+// filterEncodeHTML replaces the bytes for which encode is true with numeric character references
+func filterEncodeHTML(str string, encode func(c byte) bool) string {
+	var b strings.Builder
+	for i := 0; i < len(str); i++ {
+		if c := str[i]; encode(c) {
+			b.WriteString("&#" + strconv.Itoa(int(c)) + ";")
+		} else {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// filterKeep removes all characters except the ASCII digits, the characters of allowed and
+// the ASCII letters if letters is true
+func filterKeep(str, allowed string, letters bool) string {
+	b := make([]byte, 0, len(str))
+	for i := 0; i < len(str); i++ {
+		c := str[i]
+		if c >= '0' && c <= '9' || letters && isAlnum(c) || strings.IndexByte(allowed, c) >= 0 {
+			b = append(b, c)
+		}
+	}
+	return string(b)
+}
+
+// isAlnum reports if c is an ASCII letter or digit
+func isAlnum(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
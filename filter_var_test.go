@@ -0,0 +1,97 @@
+package php
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFilterVar(t *testing.T) {
+	tests := []struct {
+		value   interface{}
+		filter  int
+		options map[string]interface{}
+		want    interface{}
+		ok      bool
+	}{
+		{"42", FilterValidateInt, nil, 42, true},
+		{" 42 ", FilterValidateInt, nil, 42, true},
+		{"+5", FilterValidateInt, nil, 5, true},
+		{"42", FilterValidateInt, map[string]interface{}{"min_range": 50}, false, false},
+		{"042", FilterValidateInt, nil, false, false},
+		{"042", FilterValidateInt, map[string]interface{}{"flags": FilterFlagAllowOctal}, 34, true},
+		{"0x1A", FilterValidateInt, map[string]interface{}{"flags": FilterFlagAllowHex}, 26, true},
+		{"abc", FilterValidateInt, map[string]interface{}{"default": 7}, 7, false},
+		{"1.5", FilterValidateFloat, nil, 1.5, true},
+		{"1e3", FilterValidateFloat, nil, 1000.0, true},
+		{"1,000.5", FilterValidateFloat, map[string]interface{}{"flags": FilterFlagAllowThousand}, 1000.5, true},
+		{"1,000.5", FilterValidateFloat, nil, false, false},
+		{"yes", FilterValidateBool, nil, true, true},
+		{"off", FilterValidateBool, nil, false, true},
+		{"maybe", FilterValidateBool, nil, false, false},
+		{"maybe", FilterValidateBool, map[string]interface{}{"flags": FilterNullOnFailure}, nil, false},
+		{"user@example.com", FilterValidateEmail, nil, "user@example.com", true},
+		{"user@localhost", FilterValidateEmail, nil, false, false},
+		{"http://example.com/path?q=1", FilterValidateURL, nil, "http://example.com/path?q=1", true},
+		{"example.com", FilterValidateURL, nil, false, false},
+		{"http://example.com", FilterValidateURL, map[string]interface{}{"flags": FilterFlagPathRequired}, false, false},
+		{"192.168.1.1", FilterValidateIP, nil, "192.168.1.1", true},
+		{"192.168.1.1", FilterValidateIP, map[string]interface{}{"flags": FilterFlagNoPrivRange}, false, false},
+		{"256.1.1.1", FilterValidateIP, nil, false, false},
+		{"::1", FilterValidateIP, map[string]interface{}{"flags": FilterFlagIPv4}, false, false},
+		{"01:23:45:67:89:ab", FilterValidateMAC, nil, "01:23:45:67:89:ab", true},
+		{"01-23-45-67-89-ab", FilterValidateMAC, nil, "01-23-45-67-89-ab", true},
+		{"0123.4567.89ab", FilterValidateMAC, nil, "0123.4567.89ab", true},
+		{"a1b-2+3", FilterSanitizeNumberInt, nil, "1-2+3", true},
+		{"a(b)@c.com", FilterSanitizeEmail, nil, "ab@c.com", true},
+		{"<a href='x'>", FilterSanitizeSpecialChars, nil, "&#60;a href=&#39;x&#39;&#62;", true},
+		{[]int{1}, FilterDefault, nil, false, false},
+	}
+	for _, test := range tests {
+		got, ok := FilterVar(test.value, test.filter, test.options)
+		if got != test.want || ok != test.ok {
+			t.Errorf("FilterVar(%#v, %d, %v) = %#v, %v, want %#v, %v", test.value, test.filter, test.options, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestCtype(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(string) bool
+		text string
+		want bool
+	}{
+		{"CtypeDigit", CtypeDigit, "123", true},
+		{"CtypeDigit", CtypeDigit, "12.3", false},
+		{"CtypeDigit", CtypeDigit, "", false},
+		{"CtypeXdigit", CtypeXdigit, "AbCdEf09", true},
+		{"CtypeSpace", CtypeSpace, " \t\r\n\v\f", true},
+		{"CtypePunct", CtypePunct, "!@#", true},
+		{"CtypeAlnum", CtypeAlnum, "abc1", true},
+		{"CtypeAlpha", CtypeAlpha, "abc1", false},
+		{"CtypeUpper", CtypeUpper, "ABc", false},
+		{"CtypeLower", CtypeLower, "abc", true},
+		{"CtypeCntrl", CtypeCntrl, "\x00\x1f\x7f", true},
+		{"CtypePrint", CtypePrint, "a b", true},
+		{"CtypeGraph", CtypeGraph, "a b", false},
+		{"CtypeAlpha", CtypeAlpha, "é", false},
+	}
+	for _, test := range tests {
+		if got := test.fn(test.text); got != test.want {
+			t.Errorf("%s(%q) = %v, want %v", test.name, test.text, got, test.want)
+		}
+	}
+}
+
+func TestFilterCallback(t *testing.T) {
+	upper := func(s string) interface{} { return strings.ToUpper(s) }
+	if got, ok := FilterVar("abc", FilterCallback, map[string]interface{}{"callback": upper}); !ok || got != "ABC" {
+		t.Errorf("FilterVar with callback = %v, %v", got, ok)
+	}
+	if got, ok := FilterVar("abc", FilterCallback); ok || got != false {
+		t.Errorf("FilterVar without callback = %v, %v", got, ok)
+	}
+	if got, ok := FilterVar("abc", FilterCallback, map[string]interface{}{"callback": "strtoupper"}); ok || got != false {
+		t.Errorf("FilterVar with a string callback = %v, %v", got, ok)
+	}
+}
@@ -0,0 +1,11 @@
+module php
+
+go 1.24.0
+
+require (
+	github.com/rivo/uniseg v0.4.7
+	golang.org/x/crypto v0.48.0
+	golang.org/x/text v0.34.0
+)
+
+require golang.org/x/sys v0.41.0 // indirect
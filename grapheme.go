@@ -0,0 +1,172 @@
+package php
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+)
+
+// Extract types of GraphemeExtract, the same as PHP's GRAPHEME_EXTR_* constants
+const (
+	// GraphemeExtrCount extracts the number of grapheme clusters
+	GraphemeExtrCount int = 0
+	// GraphemeExtrMaxbytes extracts grapheme clusters up to the number of bytes
+	GraphemeExtrMaxbytes int = 1
+	// GraphemeExtrMaxchars extracts grapheme clusters up to the number of characters (code points)
+	GraphemeExtrMaxchars int = 2
+)
+
+// GraphemeStrlen get string length in grapheme units
+//
+// The grapheme clusters are the user-perceived characters of Unicode UAX #29, so an emoji ZWJ
+// sequence like "👩‍👩‍👧", a flag like "🇨🇳" or "e" followed by a combining accent is counted as 1.
+//
+// see http://php.net/manual/en/function.grapheme-strlen.php
+func GraphemeStrlen(str string) int {
+	return uniseg.GraphemeClusterCount(str)
+}
+
+// GraphemeSubstr return part of a string counted in grapheme units
+//
+// offset and the optional length are counted in grapheme clusters and can be negative to count from
+// the end of str, like PHP's substr. Without length the substring until the end of str is returned.
+//
+// see http://php.net/manual/en/function.grapheme-substr.php
+func GraphemeSubstr(str string, offset int, length ...int) string {
+	bounds := graphemeBoundaries(str)
+	n := len(bounds) - 1
+	if offset < 0 {
+		offset += n
+		if offset < 0 {
+			offset = 0
+		}
+	}
+	if offset > n {
+		return ""
+	}
+	end := n
+	if len(length) > 0 {
+		if length[0] < 0 {
+			end = n + length[0]
+		} else if offset+length[0] < n {
+			end = offset + length[0]
+		}
+	}
+	if end <= offset {
+		return ""
+	}
+	return str[bounds[offset]:bounds[end]]
+}
+
+// GraphemeStrpos find position in grapheme units of first occurrence of a string
+//
+// The match must start and end on grapheme cluster boundaries, so "e" is not found in "é".
+// The optional offset is counted in grapheme clusters and can be negative to count from the end of
+// haystack. return -1 if can not find the substring or offset is out of haystack.
+//
+// see http://php.net/manual/en/function.grapheme-strpos.php
+func GraphemeStrpos(haystack, needle string, offset ...int) int {
+	bounds := graphemeBoundaries(haystack)
+	n := len(bounds) - 1
+	start := 0
+	if len(offset) > 0 {
+		start = offset[0]
+	}
+	if start < 0 {
+		start += n
+	}
+	if start < 0 || start > n {
+		return -1
+	}
+	if needle == "" {
+		return start
+	}
+
+	isBoundary := func(pos int) (int, bool) {
+		i := sort.SearchInts(bounds, pos)
+		return i, i < len(bounds) && bounds[i] == pos
+	}
+	for from := bounds[start]; from < len(haystack); {
+		pos := strings.Index(haystack[from:], needle)
+		if pos < 0 {
+			break
+		}
+		pos += from
+		if i, ok := isBoundary(pos); ok {
+			if _, ok := isBoundary(pos + len(needle)); ok {
+				return i
+			}
+		}
+		from = pos + 1
+	}
+	return -1
+}
+
+// GraphemeExtract extract a sequence of grapheme clusters from a text buffer
+//
+// extractType is GraphemeExtrCount, GraphemeExtrMaxbytes or GraphemeExtrMaxchars, and size is the
+// number of the grapheme clusters, bytes or characters. offset is the byte position to start from and
+// can be negative to count from the end, it's moved to the next character if it's in the middle of one.
+// The byte position after the extracted string is stored to the optional next.
+// .eg GraphemeExtract("🇨🇳🇺🇸", 1, GraphemeExtrCount, 0) returns "🇨🇳"
+//
+// see http://php.net/manual/en/function.grapheme-extract.php
+func GraphemeExtract(haystack string, size, extractType, offset int, next ...*int) (string, error) {
+	if size < 0 {
+		return "", errors.New("size must be greater than or equal to 0")
+	}
+	if extractType < GraphemeExtrCount || extractType > GraphemeExtrMaxchars {
+		return "", errors.New("extract type must be GraphemeExtrCount, GraphemeExtrMaxbytes or GraphemeExtrMaxchars")
+	}
+	if offset < 0 {
+		offset += len(haystack)
+	}
+	if offset < 0 || (offset >= len(haystack) && haystack != "") {
+		return "", errors.New("offset must be contained in haystack")
+	}
+	for offset < len(haystack) && !utf8.RuneStart(haystack[offset]) {
+		offset++
+	}
+
+	end := offset
+	count := 0
+	state := -1
+	for rest := haystack[offset:]; rest != ""; {
+		var cluster string
+		cluster, rest, _, state = uniseg.FirstGraphemeClusterInString(rest, state)
+		switch extractType {
+		case GraphemeExtrCount:
+			count++
+		case GraphemeExtrMaxbytes:
+			count += len(cluster)
+		case GraphemeExtrMaxchars:
+			count += utf8.RuneCountInString(cluster)
+		}
+		if count > size {
+			break
+		}
+		end += len(cluster)
+	}
+
+	if len(next) > 0 && next[0] != nil {
+		*next[0] = end
+	}
+	return haystack[offset:end], nil
+}
+
+// graphemeBoundaries returns the byte offsets of the grapheme cluster boundaries of str,
+// including 0 and len(str)
+func graphemeBoundaries(str string) []int {
+	bounds := []int{0}
+	state := -1
+	for rest, pos := str, 0; rest != ""; {
+		var cluster string
+		cluster, rest, _, state = uniseg.FirstGraphemeClusterInString(rest, state)
+		pos += len(cluster)
+		bounds = append(bounds, pos)
+	}
+	return bounds
+}
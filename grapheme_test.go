@@ -0,0 +1,142 @@
+package php
+
+import (
+	"bufio"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestGraphemeBreakTest checks every line of GraphemeBreakTest.txt of Unicode UAX #29, "÷" is a
+// grapheme cluster boundary and "×" is not
+func TestGraphemeBreakTest(t *testing.T) {
+	file, err := os.Open("testdata/GraphemeBreakTest.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	lines := 0
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		lines++
+
+		var b strings.Builder
+		var want []int
+		var clusters []string
+		start := 0
+		for _, field := range fields {
+			switch field {
+			case "÷":
+				want = append(want, b.Len())
+				if b.Len() > start {
+					clusters = append(clusters, b.String()[start:])
+					start = b.Len()
+				}
+			case "×":
+			default:
+				r, err := strconv.ParseUint(field, 16, 32)
+				if err != nil {
+					t.Fatalf("line %d: %v", line, err)
+				}
+				b.WriteRune(rune(r))
+			}
+		}
+		str := b.String()
+
+		if got := graphemeBoundaries(str); !reflect.DeepEqual(got, want) {
+			t.Errorf("line %d: boundaries of %+q = %v, want %v", line, str, got, want)
+			continue
+		}
+		if got := GraphemeStrlen(str); got != len(clusters) {
+			t.Errorf("line %d: GraphemeStrlen(%+q) = %d, want %d", line, str, got, len(clusters))
+		}
+		for i, cluster := range clusters {
+			if got := GraphemeSubstr(str, i, 1); got != cluster {
+				t.Errorf("line %d: GraphemeSubstr(%+q, %d, 1) = %+q, want %+q", line, str, i, got, cluster)
+			}
+			if got := GraphemeStrpos(str, cluster); got < 0 || got > i {
+				t.Errorf("line %d: GraphemeStrpos(%+q, %+q) = %d, want at most %d", line, str, cluster, got, i)
+			}
+			next := 0
+			if got, err := GraphemeExtract(str, 1, GraphemeExtrCount, want[i], &next); err != nil || got != cluster || next != want[i+1] {
+				t.Errorf("line %d: GraphemeExtract(%+q, 1, GraphemeExtrCount, %d) = %+q, %d, %v, want %+q, %d", line, str, want[i], got, next, err, cluster, want[i+1])
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if lines == 0 {
+		t.Fatal("no test in testdata/GraphemeBreakTest.txt")
+	}
+}
+
+func TestGraphemeSubstr(t *testing.T) {
+	const (
+		family = "👨‍👩‍👧‍👦"
+		flags  = "🇨🇳🇺🇸🇯🇵"
+		accent = "e\u0301"
+	)
+	tests := []struct {
+		str    string
+		offset int
+		length []int
+		want   string
+	}{
+		{"a" + family + "b", 1, []int{1}, family},
+		{"a" + family + "b", 0, []int{2}, "a" + family},
+		{"a" + family + "b", -2, nil, family + "b"},
+		{family + family, 1, nil, family},
+		{"👩‍❤️‍💋‍👨!", 0, []int{1}, "👩‍❤️‍💋‍👨"},
+		{flags, 1, []int{1}, "🇺🇸"},
+		{flags, -1, nil, "🇯🇵"},
+		{flags, 0, []int{-1}, "🇨🇳🇺🇸"},
+		{"🇨🇳🇺", 1, nil, "🇺"},
+		{"caf" + accent + "s", 3, []int{1}, accent},
+		{"caf" + accent + "s", 4, nil, "s"},
+		{"x\u0308\u0301y", 0, []int{1}, "x\u0308\u0301"},
+		{"한국어", 1, []int{1}, "국"},
+		{"각ᄀ", 0, []int{1}, "각"},
+		{"\r\na", 0, []int{1}, "\r\n"},
+		{accent, 1, nil, ""},
+		{accent, 2, nil, ""},
+	}
+	for _, test := range tests {
+		if got := GraphemeSubstr(test.str, test.offset, test.length...); got != test.want {
+			t.Errorf("GraphemeSubstr(%+q, %d, %v) = %+q, want %+q", test.str, test.offset, test.length, got, test.want)
+		}
+	}
+}
+
+func TestGraphemeStrpos(t *testing.T) {
+	tests := []struct {
+		haystack, needle string
+		offset           []int
+		want             int
+	}{
+		{"a👨‍👩‍👧b👨", "👨", nil, 3},
+		{"a👨‍👩‍👧b", "👨‍👩‍👧", nil, 1},
+		{"🇨🇳🇺🇸", "🇺🇸", nil, 1},
+		{"🇨🇳🇺🇸", "🇳🇺", nil, -1},
+		{"cafe\u0301 cafe", "e", nil, 8},
+		{"cafe\u0301 cafe", "e\u0301", nil, 3},
+		{"cafe\u0301 cafe\u0301", "e\u0301", []int{4}, 8},
+		{"abc", "", []int{-1}, 2},
+	}
+	for _, test := range tests {
+		if got := GraphemeStrpos(test.haystack, test.needle, test.offset...); got != test.want {
+			t.Errorf("GraphemeStrpos(%+q, %+q, %v) = %d, want %d", test.haystack, test.needle, test.offset, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,342 @@
+package php
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha3"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/adler32"
+	"hash/crc32"
+	"hash/fnv"
+	"io"
+	"math/bits"
+	"os"
+	"sort"
+	"strings"
+)
+
+// hashAlgos maps the PHP algorithm names to the hash constructors, only the
+// cryptographic algorithms can be used with HMAC
+var hashAlgos = map[string]struct {
+	new    func() hash.Hash
+	crypto bool
+}{
+	"md5":        {md5.New, true},
+	"sha1":       {sha1.New, true},
+	"sha224":     {sha256.New224, true},
+	"sha256":     {sha256.New, true},
+	"sha384":     {sha512.New384, true},
+	"sha512/224": {sha512.New512_224, true},
+	"sha512/256": {sha512.New512_256, true},
+	"sha512":     {sha512.New, true},
+	"sha3-224":   {func() hash.Hash { return sha3.New224() }, true},
+	"sha3-256":   {func() hash.Hash { return sha3.New256() }, true},
+	"sha3-384":   {func() hash.Hash { return sha3.New384() }, true},
+	"sha3-512":   {func() hash.Hash { return sha3.New512() }, true},
+	"adler32":    {func() hash.Hash { return adler32.New() }, false},
+	"crc32b":     {func() hash.Hash { return crc32.NewIEEE() }, false},
+	"crc32c":     {func() hash.Hash { return crc32.New(crc32.MakeTable(crc32.Castagnoli)) }, false},
+	"fnv132":     {func() hash.Hash { return fnv.New32() }, false},
+	"fnv1a32":    {func() hash.Hash { return fnv.New32a() }, false},
+	"fnv164":     {func() hash.Hash { return fnv.New64() }, false},
+	"fnv1a64":    {func() hash.Hash { return fnv.New64a() }, false},
+	"xxh64":      {func() hash.Hash { return newXXH64() }, false},
+}
+
+// HashContext is an incremental hashing context created by HashInit
+type HashContext struct {
+	algo  string
+	h     hash.Hash
+	outer []byte // the outer padded key of HMAC, nil if it's not HMAC
+}
+
+// HashAlgos return a list of registered hashing algorithms
+//
+// see http://php.net/manual/en/function.hash-algos.php
+func HashAlgos() []string {
+	res := make([]string, 0, len(hashAlgos))
+	for algo := range hashAlgos {
+		res = append(res, algo)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Hash generate a hash value (message digest)
+//
+// algo is the name of the algorithm like "sha256", "sha3-256", "crc32b" or "xxh64", see HashAlgos.
+// The lowercase hex digits are returned unless the optional binary is true.
+//
+// see http://php.net/manual/en/function.hash.php
+func Hash(algo, data string, binary ...bool) (string, error) {
+	ctx, err := HashInit(algo)
+	if err != nil {
+		return "", err
+	}
+	HashUpdate(ctx, data)
+	return HashFinal(ctx, binary...), nil
+}
+
+// HashFile generate a hash value using the contents of a given file
+//
+// see http://php.net/manual/en/function.hash-file.php
+func HashFile(algo, filename string, binary ...bool) (string, error) {
+	ctx, err := HashInit(algo)
+	if err != nil {
+		return "", err
+	}
+	if err := HashUpdateFile(ctx, filename); err != nil {
+		return "", err
+	}
+	return HashFinal(ctx, binary...), nil
+}
+
+// HashHmac generate a keyed hash value using the HMAC method
+//
+// Only the cryptographic algorithms can be used, crc32b, fnv1a64 and the like are rejected.
+//
+// see http://php.net/manual/en/function.hash-hmac.php
+func HashHmac(algo, data, key string, binary ...bool) (string, error) {
+	ctx, err := HashInit(algo, key)
+	if err != nil {
+		return "", err
+	}
+	HashUpdate(ctx, data)
+	return HashFinal(ctx, binary...), nil
+}
+
+// HashEquals timing attack safe string comparison
+//
+// see http://php.net/manual/en/function.hash-equals.php
+func HashEquals(knownString, userString string) bool {
+	return subtle.ConstantTimeCompare([]byte(knownString), []byte(userString)) == 1
+}
+
+// HashInit initialize an incremental hashing context
+//
+// The context is for HMAC if the optional key is given, like PHP's HASH_HMAC flag.
+// .eg ctx, _ := HashInit("sha256"); HashUpdate(ctx, "abc"); HashFinal(ctx)
+//
+// see http://php.net/manual/en/function.hash-init.php
+func HashInit(algo string, key ...string) (*HashContext, error) {
+	algo = strings.ToLower(algo)
+	a, ok := hashAlgos[algo]
+	if !ok {
+		return nil, fmt.Errorf("unknown hashing algorithm: %s", algo)
+	}
+	ctx := &HashContext{algo: algo, h: a.new()}
+	if len(key) == 0 {
+		return ctx, nil
+	}
+	if !a.crypto {
+		return nil, fmt.Errorf("non-cryptographic hashing algorithm: %s", algo)
+	}
+
+	k := []byte(key[0])
+	if size := ctx.h.BlockSize(); len(k) > size {
+		ctx.h.Write(k)
+		k = ctx.h.Sum(nil)
+		ctx.h.Reset()
+	}
+	inner := make([]byte, ctx.h.BlockSize())
+	ctx.outer = make([]byte, ctx.h.BlockSize())
+	copy(inner, k)
+	copy(ctx.outer, k)
+	for i := range inner {
+		inner[i] ^= 0x36
+		ctx.outer[i] ^= 0x5c
+	}
+	ctx.h.Write(inner)
+	return ctx, nil
+}
+
+// HashUpdate pump data into an active hashing context
+//
+// see http://php.net/manual/en/function.hash-update.php
+func HashUpdate(ctx *HashContext, data string) {
+	io.WriteString(ctx.h, data)
+}
+
+// HashUpdateStream pump data from a reader into an active hashing context
+func HashUpdateStream(ctx *HashContext, r io.Reader) (int64, error) {
+	return io.Copy(ctx.h, r)
+}
+
+// HashUpdateFile pump data into an active hashing context from a file
+//
+// see http://php.net/manual/en/function.hash-update-file.php
+func HashUpdateFile(ctx *HashContext, filename string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = HashUpdateStream(ctx, f)
+	return err
+}
+
+// HashFinal finalize an incremental hash and return resulting digest
+//
+// The context can not be used after it, use HashCopy before if it's needed.
+//
+// see http://php.net/manual/en/function.hash-final.php
+func HashFinal(ctx *HashContext, binary ...bool) string {
+	sum := ctx.h.Sum(nil)
+	if ctx.outer != nil {
+		h := hashAlgos[ctx.algo].new()
+		h.Write(ctx.outer)
+		h.Write(sum)
+		sum = h.Sum(nil)
+	}
+	if len(binary) > 0 && binary[0] {
+		return string(sum)
+	}
+	return hex.EncodeToString(sum)
+}
+
+// HashCopy copy hashing context
+//
+// see http://php.net/manual/en/function.hash-copy.php
+func HashCopy(ctx *HashContext) (*HashContext, error) {
+	m, ok := ctx.h.(encoding.BinaryMarshaler)
+	if !ok {
+		return nil, errors.New("the hashing context can not be copied")
+	}
+	state, err := m.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	h := hashAlgos[ctx.algo].new()
+	if err := h.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+		return nil, err
+	}
+	return &HashContext{algo: ctx.algo, h: h, outer: ctx.outer}, nil
+}
+
+const (
+	xxhPrime1 uint64 = 11400714785074694791
+	xxhPrime2 uint64 = 14029467366897019727
+	xxhPrime3 uint64 = 1609587929392839161
+	xxhPrime4 uint64 = 9650029242287828579
+	xxhPrime5 uint64 = 2870177450012600261
+)
+
+// xxh64 is the XXH64 hash with seed 0, the sum is in the canonical big-endian form like PHP's
+type xxh64 struct {
+	v     [4]uint64
+	total uint64
+	mem   [32]byte
+	n     int
+}
+
+func newXXH64() *xxh64 {
+	x := &xxh64{}
+	x.Reset()
+	return x
+}
+
+func (x *xxh64) Reset() {
+	p1 := xxhPrime1
+	x.v = [4]uint64{p1 + xxhPrime2, xxhPrime2, 0, 0 - p1}
+	x.total, x.n = 0, 0
+}
+
+func (x *xxh64) Size() int { return 8 }
+
+func (x *xxh64) BlockSize() int { return 32 }
+
+func (x *xxh64) Write(p []byte) (int, error) {
+	l := len(p)
+	x.total += uint64(l)
+	if x.n+len(p) < 32 {
+		x.n += copy(x.mem[x.n:], p)
+		return l, nil
+	}
+	if x.n > 0 {
+		p = p[copy(x.mem[x.n:], p):]
+		x.stripe(x.mem[:])
+		x.n = 0
+	}
+	for ; len(p) >= 32; p = p[32:] {
+		x.stripe(p)
+	}
+	x.n = copy(x.mem[:], p)
+	return l, nil
+}
+
+func (x *xxh64) stripe(p []byte) {
+	for i := range x.v {
+		x.v[i] = xxhRound(x.v[i], binary.LittleEndian.Uint64(p[8*i:]))
+	}
+}
+
+func (x *xxh64) Sum(b []byte) []byte {
+	var h uint64
+	if x.total >= 32 {
+		h = bits.RotateLeft64(x.v[0], 1) + bits.RotateLeft64(x.v[1], 7) + bits.RotateLeft64(x.v[2], 12) + bits.RotateLeft64(x.v[3], 18)
+		for _, v := range x.v {
+			h = (h^xxhRound(0, v))*xxhPrime1 + xxhPrime4
+		}
+	} else {
+		h = xxhPrime5
+	}
+	h += x.total
+
+	p := x.mem[:x.n]
+	for ; len(p) >= 8; p = p[8:] {
+		h ^= xxhRound(0, binary.LittleEndian.Uint64(p))
+		h = bits.RotateLeft64(h, 27)*xxhPrime1 + xxhPrime4
+	}
+	if len(p) >= 4 {
+		h ^= uint64(binary.LittleEndian.Uint32(p)) * xxhPrime1
+		h = bits.RotateLeft64(h, 23)*xxhPrime2 + xxhPrime3
+		p = p[4:]
+	}
+	for _, c := range p {
+		h ^= uint64(c) * xxhPrime5
+		h = bits.RotateLeft64(h, 11) * xxhPrime1
+	}
+
+	h ^= h >> 33
+	h *= xxhPrime2
+	h ^= h >> 29
+	h *= xxhPrime3
+	h ^= h >> 32
+	return binary.BigEndian.AppendUint64(b, h)
+}
+
+func (x *xxh64) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, 8*5+1+32)
+	for _, v := range x.v {
+		b = binary.BigEndian.AppendUint64(b, v)
+	}
+	b = binary.BigEndian.AppendUint64(b, x.total)
+	b = append(b, byte(x.n))
+	return append(b, x.mem[:]...), nil
+}
+
+func (x *xxh64) UnmarshalBinary(b []byte) error {
+	if len(b) != 8*5+1+32 {
+		return errors.New("xxh64: invalid hash state")
+	}
+	for i := range x.v {
+		x.v[i] = binary.BigEndian.Uint64(b[8*i:])
+	}
+	x.total = binary.BigEndian.Uint64(b[32:])
+	x.n = int(b[40])
+	copy(x.mem[:], b[41:])
+	return nil
+}
+
+func xxhRound(acc, input uint64) uint64 {
+	return bits.RotateLeft64(acc+input*xxhPrime2, 31) * xxhPrime1
+}
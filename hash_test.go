@@ -0,0 +1,106 @@
+package php
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHash(t *testing.T) {
+	const fox = "The quick brown fox jumped over the lazy dog."
+	tests := []struct {
+		algo, data, want string
+	}{
+		{"md5", "", "d41d8cd98f00b204e9800998ecf8427e"},
+		{"md5", fox, "5c6ffbdd40d9556b73a21e63c3e0e904"},
+		{"sha1", fox, "c0854fb9fb03c41cce3802cb0d220529e6eef94e"},
+		{"sha256", fox, "68b1282b91de2c054c36629cb8dd447f12f096d3e3c587978dc2248444633483"},
+		{"sha512", fox, "0a8c150176c2ba391d7f1670ef4955cd99d3c3ec8cf06198cec30d436f2ac0c9b64229b5a54bdbd5563160503ce992a74be528761da9d0c48b7c74627302eb25"},
+		{"sha3-256", fox, "0ae9f030636d8ec4973e95b5f910b764bd70e9bb1224416753db480079426104"},
+		{"crc32b", fox, "82a34642"},
+		{"crc32b", "123456789", "cbf43926"},
+		{"crc32c", "123456789", "e3069283"},
+		{"adler32", fox, "7bf1105e"},
+		{"fnv1a32", "", "811c9dc5"},
+		{"xxh64", "", "ef46db3751d8e999"},
+		{"xxh64", "abc", "44bc2cf5ad770999"},
+	}
+	for _, test := range tests {
+		got, err := Hash(test.algo, test.data)
+		if err != nil || got != test.want {
+			t.Errorf("Hash(%q, %q) = %q, %v, want %q", test.algo, test.data, got, err, test.want)
+		}
+	}
+
+	if got, err := Hash("sha256", "abc", true); err != nil || got[:4] != "\xba\x78\x16\xbf" || len(got) != 32 {
+		t.Errorf("Hash(sha256, abc, true) = %q, %v", got, err)
+	}
+	if _, err := Hash("md4x", "abc"); err == nil {
+		t.Error("Hash with an unknown algorithm expected an error")
+	}
+}
+
+func TestHashHmac(t *testing.T) {
+	const fox = "The quick brown fox jumped over the lazy dog."
+	tests := []struct {
+		algo, data, key, want string
+	}{
+		{"md5", fox, "secret", "7eb2b5c37443418fc77c136dd20e859c"},
+		{"sha1", "", "", "fbdb1d1b18aa6c08324b7d64b71fb76370690e1d"},
+		// the key is longer than the block size of 64 bytes, so it's hashed first
+		{"sha256", fox, strings.Repeat("k", 100), "209280752172b2c45bf90890b76cb42d9617813341d3d48e631ef0b0a89a1f57"},
+	}
+	for _, test := range tests {
+		got, err := HashHmac(test.algo, test.data, test.key)
+		if err != nil || got != test.want {
+			t.Errorf("HashHmac(%q, %q, %q) = %q, %v, want %q", test.algo, test.data, test.key, got, err, test.want)
+		}
+	}
+	if _, err := HashHmac("crc32b", fox, "secret"); err == nil {
+		t.Error("HashHmac with crc32b expected an error")
+	}
+}
+
+func TestHashIncremental(t *testing.T) {
+	ctx, err := HashInit("sha256")
+	if err != nil {
+		t.Fatal(err)
+	}
+	HashUpdate(ctx, "a")
+	HashUpdate(ctx, "b")
+	copied, err := HashCopy(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	HashUpdate(copied, "c")
+	if got := HashFinal(ctx); got != "fb8e20fc2e4c3f248c60c39bd652f3c1347298bb977b8b4d5903b85055620603" {
+		t.Errorf("HashFinal(ab) = %q", got)
+	}
+	if got := HashFinal(copied); got != "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad" {
+		t.Errorf("HashFinal(abc) = %q", got)
+	}
+
+	ctx, err = HashInit("md5", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	HashUpdate(ctx, "The quick brown fox ")
+	HashUpdate(ctx, "jumped over the lazy dog.")
+	if got := HashFinal(ctx); got != "7eb2b5c37443418fc77c136dd20e859c" {
+		t.Errorf("HashFinal(hmac md5) = %q", got)
+	}
+}
+
+func TestHashFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "fox.txt")
+	if err := os.WriteFile(filename, []byte("The quick brown fox jumped over the lazy dog."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := HashFile("md5", filename); err != nil || got != "5c6ffbdd40d9556b73a21e63c3e0e904" {
+		t.Errorf("HashFile(md5) = %q, %v", got, err)
+	}
+	if _, err := HashFile("md5", filename+".missing"); err == nil {
+		t.Error("HashFile with a missing file expected an error")
+	}
+}
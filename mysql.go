@@ -264,6 +264,34 @@ func (d *DB) Select(params ...string) (map[int]map[string]string, error) {
 	return rows, err
 }
 
+// FindValue return the first row of result as an array Value and error
+// the columns keep their order and type as returned by the driver, NULL columns are null,
+// a null Value is returned if there is no row
+// .eg DB.FindValue()
+func (d *DB) FindValue() (Value, error) {
+	defer d.Clear()
+	d.limit = 1
+	query := fmt.Sprintf(QuerySelect, d.fields, parseTable(d)+parseWhere(d, false)+parseOrder(d, true)+parseWhere(d, true)+parseOrder(d, false)+parseLimit(d)+parseOffset(d))
+	rows, err := searchValue(d, query)
+	return rows.Get(0), err
+}
+
+// SelectValue return result as a list of array Value and error
+// the columns keep their order and type as returned by the driver, NULL columns are null
+// support to select by the query given in
+// .eg DB.SelectValue()
+// .eg DB.SelectValue("select * from user")
+func (d *DB) SelectValue(params ...string) (Value, error) {
+	var query string
+	defer d.Clear()
+	if len(params) > 0 {
+		query = params[0]
+	} else {
+		query = fmt.Sprintf(QuerySelect, d.fields, parseTable(d)+parseWhere(d, false)+parseOrder(d, true)+parseWhere(d, true)+parseOrder(d, false)+parseLimit(d)+parseOffset(d))
+	}
+	return searchValue(d, query)
+}
+
 // Count return count and error
 // .eg DB.Count()
 // .eg DB.Count("id")
@@ -310,6 +338,24 @@ func (d *DB) Insert(fields []string, data map[int]map[string]interface{}) (int64
 	return res.LastInsertId()
 }
 
+// InsertValue return lastid and error, data is a list of the rows as array Value like the result of SelectValue
+// .eg DB.InsertValue([]string{"name", "age"}, NewArray(NewValue(map[string]interface{}{"name": "apple", "age": 12})))
+func (d *DB) InsertValue(fields []string, data Value) (int64, error) {
+	if data.Kind() != KindArray {
+		defer d.Clear()
+		return 0, fmt.Errorf("insert data must be an array, %s given", data.Kind())
+	}
+	rows := make(map[int]map[string]interface{}, data.Len())
+	for i, row := range data.Values() {
+		if row.Kind() != KindArray {
+			defer d.Clear()
+			return 0, fmt.Errorf("insert row %d must be an array, %s given", i, row.Kind())
+		}
+		rows[i] = valueColumns(row)
+	}
+	return d.Insert(fields, rows)
+}
+
 // InsertSelect return lastid and error
 // .eg DB.InsertSelect("SELECT * FROM user_bak")
 func (d *DB) InsertSelect(s string) (int64, error) {
@@ -346,6 +392,16 @@ func (d *DB) Update(data map[string]interface{}) (int64, error) {
 	return res.RowsAffected()
 }
 
+// UpdateValue return affect-rows and error, data is an array Value of the columns
+// .eg DB.UpdateValue(NewValue(map[string]interface{}{"name": "king"}))
+func (d *DB) UpdateValue(data Value) (int64, error) {
+	if data.Kind() != KindArray {
+		defer d.Clear()
+		return 0, fmt.Errorf("update data must be an array, %s given", data.Kind())
+	}
+	return d.Update(valueColumns(data))
+}
+
 // Delete return affect-rows and error
 // .eg DB.Delete()
 func (d *DB) Delete() (int64, error) {
@@ -505,6 +561,45 @@ func search(d *DB, q string) (*sql.Rows, error) {
 	return query(d, q)
 }
 
+// searchValue do query and scan the rows into a list of array Value
+func searchValue(d *DB, query string) (Value, error) {
+	rows := NewArray()
+	q, err := search(d, query)
+	if err != nil {
+		return rows, err
+	}
+	defer q.Close()
+	cols, _ := q.Columns()
+	values := make([]Value, len(cols))
+	scans := make([]interface{}, len(cols))
+	for i := range values {
+		scans[i] = &values[i]
+	}
+	for q.Next() {
+		if err := q.Scan(scans...); err != nil {
+			return rows, err
+		}
+		row := NewArray()
+		for k, v := range values {
+			row.Set(cols[k], v)
+		}
+		rows.Append(row)
+	}
+	return rows, q.Err()
+}
+
+// valueColumns converts the array Value row to the columns of Insert and Update, null columns are NULL
+func valueColumns(row Value) map[string]interface{} {
+	columns := make(map[string]interface{}, row.Len())
+	row.Range(func(key, value Value) bool {
+		if !value.IsNull() {
+			columns[key.String()] = value
+		}
+		return true
+	})
+	return columns
+}
+
 // query do query
 func query(d *DB, query string) (q *sql.Rows, err error) {
 	setLastSQL(d, query)
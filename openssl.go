@@ -0,0 +1,230 @@
+package php
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Options of OpensslEncrypt and OpensslDecrypt, the same as PHP's OPENSSL_* constants
+const (
+	// OpensslRawData returns and accepts the raw binary data instead of base64
+	OpensslRawData int = 1
+	// OpensslZeroPadding disables the PKCS#7 padding, data must be a multiple of the block size
+	OpensslZeroPadding int = 2
+	// OpensslDontZeroPadKey rejects a passphrase shorter than the key instead of padding it with NUL
+	OpensslDontZeroPadKey int = 4
+)
+
+// opensslCipher describes a cipher method of OpensslEncrypt
+type opensslCipher struct {
+	keyLen int
+	ivLen  int
+	mode   string // "cbc", "ecb", "ctr" or "gcm"
+}
+
+// opensslCiphers maps the OpenSSL method names to the ciphers
+var opensslCiphers = map[string]opensslCipher{
+	"aes-128-cbc": {16, aes.BlockSize, "cbc"},
+	"aes-192-cbc": {24, aes.BlockSize, "cbc"},
+	"aes-256-cbc": {32, aes.BlockSize, "cbc"},
+	"aes-128-ecb": {16, 0, "ecb"},
+	"aes-192-ecb": {24, 0, "ecb"},
+	"aes-256-ecb": {32, 0, "ecb"},
+	"aes-128-ctr": {16, aes.BlockSize, "ctr"},
+	"aes-192-ctr": {24, aes.BlockSize, "ctr"},
+	"aes-256-ctr": {32, aes.BlockSize, "ctr"},
+	"aes-128-gcm": {16, 12, "gcm"},
+	"aes-192-gcm": {24, 12, "gcm"},
+	"aes-256-gcm": {32, 12, "gcm"},
+}
+
+// OpensslEncrypt encrypts data
+//
+// cipherAlgo is the OpenSSL method name like "aes-256-cbc", "aes-128-ecb" or "aes-256-gcm". The result
+// is base64 encoded unless OpensslRawData is in options. Like PHP, a short passphrase is padded with
+// NUL bytes and a long one is truncated to the key length, a short iv is padded with NUL bytes and a
+// long one is truncated, except for GCM which uses the iv as it is.
+// For GCM the authentication tag is stored to tag, and the optional aad is the additional
+// authenticated data, tag is ignored by the other methods and can be nil. The tag is always 16 bytes,
+// PHP's default tag_length, there is no tag_length argument. A shorter tag of PHP's tag_length 12 to 15
+// is the prefix of the 16 bytes tag, like tag[:12], and OpensslDecrypt accepts it.
+// .eg OpensslEncrypt("data", "aes-256-gcm", key, OpensslRawData, iv, &tag)
+//
+// see http://php.net/manual/en/function.openssl-encrypt.php
+func OpensslEncrypt(data, cipherAlgo, passphrase string, options int, iv string, tag *string, aad ...string) (string, error) {
+	c, block, ivb, err := opensslInit(cipherAlgo, passphrase, options, iv)
+	if err != nil {
+		return "", err
+	}
+
+	var out []byte
+	switch c.mode {
+	case "gcm":
+		if tag == nil {
+			return "", errors.New("a tag is required for AEAD cipher mode")
+		}
+		aead, err := cipher.NewGCMWithNonceSize(block, len(ivb))
+		if err != nil {
+			return "", err
+		}
+		sealed := aead.Seal(nil, ivb, []byte(data), []byte(strings.Join(aad, "")))
+		out = sealed[:len(data)]
+		*tag = string(sealed[len(data):])
+	case "ctr":
+		out = make([]byte, len(data))
+		cipher.NewCTR(block, ivb).XORKeyStream(out, []byte(data))
+	default:
+		out = []byte(data)
+		if options&OpensslZeroPadding == 0 {
+			n := aes.BlockSize - len(out)%aes.BlockSize
+			out = append(out, bytes.Repeat([]byte{byte(n)}, n)...)
+		} else if len(out)%aes.BlockSize != 0 {
+			return "", errors.New("data not multiple of block length")
+		}
+		if c.mode == "cbc" {
+			cipher.NewCBCEncrypter(block, ivb).CryptBlocks(out, out)
+		} else {
+			for i := 0; i < len(out); i += aes.BlockSize {
+				block.Encrypt(out[i:], out[i:])
+			}
+		}
+	}
+
+	if options&OpensslRawData != 0 {
+		return string(out), nil
+	}
+	return base64.StdEncoding.EncodeToString(out), nil
+}
+
+// OpensslDecrypt decrypts data
+//
+// The options, passphrase and iv are the same as OpensslEncrypt. For GCM tag is the authentication tag
+// of at least 12 bytes, an error is returned if the data or aad is not authentic.
+//
+// see http://php.net/manual/en/function.openssl-decrypt.php
+func OpensslDecrypt(data, cipherAlgo, passphrase string, options int, iv string, tag string, aad ...string) (string, error) {
+	c, block, ivb, err := opensslInit(cipherAlgo, passphrase, options, iv)
+	if err != nil {
+		return "", err
+	}
+
+	in := []byte(data)
+	if options&OpensslRawData == 0 {
+		if in, err = opensslBase64Decode(data); err != nil {
+			return "", err
+		}
+	}
+
+	switch c.mode {
+	case "gcm":
+		var aead cipher.AEAD
+		switch {
+		case len(ivb) == 12:
+			aead, err = cipher.NewGCMWithTagSize(block, len(tag))
+		case len(tag) == 16:
+			aead, err = cipher.NewGCMWithNonceSize(block, len(ivb))
+		default:
+			err = errors.New("unsupported length of the iv and tag")
+		}
+		if err != nil {
+			return "", err
+		}
+		out, err := aead.Open(nil, ivb, append(in, tag...), []byte(strings.Join(aad, "")))
+		if err != nil {
+			return "", err
+		}
+		return string(out), nil
+	case "ctr":
+		out := make([]byte, len(in))
+		cipher.NewCTR(block, ivb).XORKeyStream(out, in)
+		return string(out), nil
+	}
+
+	if len(in)%aes.BlockSize != 0 || (len(in) == 0 && options&OpensslZeroPadding == 0) {
+		return "", errors.New("data not multiple of block length")
+	}
+	out := make([]byte, len(in))
+	if c.mode == "cbc" {
+		cipher.NewCBCDecrypter(block, ivb).CryptBlocks(out, in)
+	} else {
+		for i := 0; i < len(in); i += aes.BlockSize {
+			block.Decrypt(out[i:], in[i:])
+		}
+	}
+	if options&OpensslZeroPadding == 0 {
+		n := int(out[len(out)-1])
+		if n == 0 || n > aes.BlockSize {
+			return "", errors.New("bad decrypt")
+		}
+		for _, b := range out[len(out)-n:] {
+			if int(b) != n {
+				return "", errors.New("bad decrypt")
+			}
+		}
+		out = out[:len(out)-n]
+	}
+	return string(out), nil
+}
+
+// OpensslCipherIvLength gets the cipher iv length
+//
+// see http://php.net/manual/en/function.openssl-cipher-iv-length.php
+func OpensslCipherIvLength(cipherAlgo string) (int, error) {
+	c, ok := opensslCiphers[strings.ToLower(cipherAlgo)]
+	if !ok {
+		return 0, fmt.Errorf("unknown cipher algorithm: %s", cipherAlgo)
+	}
+	return c.ivLen, nil
+}
+
+// OpensslRandomPseudoBytes generates a string of cryptographically strong pseudo-random bytes
+//
+// see http://php.net/manual/en/function.openssl-random-pseudo-bytes.php
+func OpensslRandomPseudoBytes(length int) (string, error) {
+	return RandomBytes(length)
+}
+
+// opensslInit looks up the cipher and creates its block with the key and iv fixed like PHP
+func opensslInit(cipherAlgo, passphrase string, options int, iv string) (opensslCipher, cipher.Block, []byte, error) {
+	c, ok := opensslCiphers[strings.ToLower(cipherAlgo)]
+	if !ok {
+		return c, nil, nil, fmt.Errorf("unknown cipher algorithm: %s", cipherAlgo)
+	}
+
+	key := make([]byte, c.keyLen)
+	if len(passphrase) < c.keyLen && options&OpensslDontZeroPadKey != 0 {
+		return c, nil, nil, fmt.Errorf("key length should be %d bytes", c.keyLen)
+	}
+	copy(key, passphrase)
+
+	var ivb []byte
+	if c.mode == "gcm" {
+		if iv == "" {
+			return c, nil, nil, errors.New("setting of IV length for AEAD mode failed")
+		}
+		ivb = []byte(iv)
+	} else {
+		ivb = make([]byte, c.ivLen)
+		copy(ivb, iv)
+	}
+
+	block, err := aes.NewCipher(key)
+	return c, block, ivb, err
+}
+
+// opensslBase64Decode decodes base64 like PHP's non-strict base64_decode, whitespace and missing
+// padding are accepted
+func opensslBase64Decode(data string) ([]byte, error) {
+	data = strings.Map(func(r rune) rune {
+		if r == ' ' || r == '\t' || r == '\r' || r == '\n' {
+			return -1
+		}
+		return r
+	}, data)
+	return base64.RawStdEncoding.DecodeString(strings.TrimRight(data, "="))
+}
@@ -0,0 +1,101 @@
+package php
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestOpensslEncrypt(t *testing.T) {
+	const data = "Hello, World!"
+	tests := []struct {
+		method, passphrase string
+		options            int
+		iv, data, want     string
+	}{
+		{"aes-128-cbc", "0123456789abcdef", 0, "abcdef9876543210", data, "zGRPHDZ+U7UCw0U7doBn3g=="},
+		// the passphrase is truncated to the key length
+		{"aes-128-cbc", "0123456789abcdefXYZ", 0, "abcdef9876543210", data, "zGRPHDZ+U7UCw0U7doBn3g=="},
+		// the passphrase is padded with NUL bytes
+		{"aes-256-cbc", "secret", 0, "1234567890123456", data, "wjT218cQTEVdq1oMmUc9Gw=="},
+		// the iv is padded with NUL bytes and truncated
+		{"aes-128-cbc", "0123456789abcdef", 0, "abc", data, "EfM1VdtklQrDf7h05JTPHg=="},
+		{"AES-128-CBC", "0123456789abcdef", 0, "abcdef9876543210XYZ", data, "zGRPHDZ+U7UCw0U7doBn3g=="},
+		{"aes-128-ecb", "0123456789abcdef", 0, "", data, "vk1/8UY6+ZzU2058Uot6Iw=="},
+		// a full block of padding is added to data of the block size
+		{"aes-128-ecb", "0123456789abcdef", 0, "", "0123456789abcdef", "cnJ+iB7c/QEApxhoeQm1ZTdyIuBhqSTFkc2cJ+oWPtQ="},
+		{"aes-128-cbc", "0123456789abcdef", OpensslRawData | OpensslZeroPadding, "abcdef9876543210", "0123456789abcdef",
+			"\x2b\x6b\x52\x58\x31\x27\x17\x6e\xff\xa0\xdd\x59\x36\x1f\x7e\x15"},
+		{"aes-256-ctr", "0123456789abcdef0123456789abcdef", 0, "abcdef9876543210", data, "RqHZp3DbcsLWNogDwQ=="},
+	}
+	for _, test := range tests {
+		got, err := OpensslEncrypt(test.data, test.method, test.passphrase, test.options, test.iv, nil)
+		if err != nil || got != test.want {
+			t.Errorf("OpensslEncrypt(%q, %q) = %q, %v, want %q", test.data, test.method, got, err, test.want)
+			continue
+		}
+		plain, err := OpensslDecrypt(got, test.method, test.passphrase, test.options, test.iv, "")
+		if err != nil || plain != test.data {
+			t.Errorf("OpensslDecrypt(%q, %q) = %q, %v, want %q", got, test.method, plain, err, test.data)
+		}
+	}
+
+	if _, err := OpensslEncrypt(data, "aes-128-cbc", "0123456789abcdef", OpensslZeroPadding, "abcdef9876543210", nil); err == nil {
+		t.Error("OpensslEncrypt with OpensslZeroPadding expected an error for a partial block")
+	}
+	if _, err := OpensslEncrypt(data, "aes-256-cbc", "secret", OpensslDontZeroPadKey, "1234567890123456", nil); err == nil {
+		t.Error("OpensslEncrypt with OpensslDontZeroPadKey expected an error for a short key")
+	}
+	if _, err := OpensslEncrypt(data, "des-cbc", "secret", 0, "", nil); err == nil {
+		t.Error("OpensslEncrypt with an unknown method expected an error")
+	}
+	if _, err := OpensslDecrypt("zGRPHDZ+U7UCw0U7doBn3g==", "aes-128-cbc", "fedcba9876543210", 0, "abcdef9876543210", ""); err == nil {
+		t.Error("OpensslDecrypt with a wrong key expected a bad padding error")
+	}
+}
+
+func TestOpensslGCM(t *testing.T) {
+	const (
+		key  = "0123456789abcdef"
+		iv   = "123456789012"
+		data = "Hello, World!"
+	)
+	var tag string
+	got, err := OpensslEncrypt(data, "aes-128-gcm", key, 0, iv, &tag, "header")
+	if err != nil || got != "jjQumYRCp/xe4/cYLg==" || hex.EncodeToString([]byte(tag)) != "c26723dc8e800db1f80e2bf359570587" {
+		t.Fatalf("OpensslEncrypt(gcm) = %q, %x, %v", got, tag, err)
+	}
+
+	plain, err := OpensslDecrypt(got, "aes-128-gcm", key, 0, iv, tag, "header")
+	if err != nil || plain != data {
+		t.Errorf("OpensslDecrypt(gcm) = %q, %v", plain, err)
+	}
+	// a truncated tag of 12 bytes is still accepted
+	if plain, err := OpensslDecrypt(got, "aes-128-gcm", key, 0, iv, tag[:12], "header"); err != nil || plain != data {
+		t.Errorf("OpensslDecrypt(gcm, 12 bytes tag) = %q, %v", plain, err)
+	}
+
+	tampered := []byte(tag)
+	tampered[0] ^= 1
+	if _, err := OpensslDecrypt(got, "aes-128-gcm", key, 0, iv, string(tampered), "header"); err == nil {
+		t.Error("OpensslDecrypt with a tampered tag expected an error")
+	}
+	if _, err := OpensslDecrypt(got, "aes-128-gcm", key, 0, iv, tag, "other"); err == nil {
+		t.Error("OpensslDecrypt with another aad expected an error")
+	}
+	if _, err := OpensslDecrypt(got, "aes-128-gcm", key, 0, iv, tag[:11], "header"); err == nil {
+		t.Error("OpensslDecrypt with a tag shorter than 12 bytes expected an error")
+	}
+	if _, err := OpensslEncrypt(data, "aes-128-gcm", key, 0, iv, nil); err == nil {
+		t.Error("OpensslEncrypt(gcm) without tag expected an error")
+	}
+	if _, err := OpensslEncrypt(data, "aes-128-gcm", key, 0, "", &tag); err == nil {
+		t.Error("OpensslEncrypt(gcm) without iv expected an error")
+	}
+
+	if n, err := OpensslCipherIvLength("aes-256-gcm"); err != nil || n != 12 {
+		t.Errorf("OpensslCipherIvLength(aes-256-gcm) = %d, %v", n, err)
+	}
+	if n, err := OpensslCipherIvLength("aes-128-ecb"); err != nil || n != 0 {
+		t.Errorf("OpensslCipherIvLength(aes-128-ecb) = %d, %v", n, err)
+	}
+}
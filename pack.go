@@ -0,0 +1,361 @@
+package php
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Pack pack data into binary string
+//
+// The format codes are the same as PHP's, a A Z h H c C s S n v i I l L N V q Q J P f g G d e E x X @,
+// followed by an optional repeater count or "*". The machine byte order and sizes of s S i I l L q Q f d
+// are little-endian, 16, 32, 32, 64, 32 and 64 bits, as PHP on amd64 and arm64. The arguments are
+// converted like PHP, so numeric strings can be packed as integers.
+// .eg Pack("nvc*", 0x1234, 0x5678, 65, 66) returns "\x12\x34\x78\x56AB"
+//
+// see http://php.net/manual/en/function.pack.php
+func Pack(format string, args ...interface{}) (string, error) {
+	var out []byte
+	pos, current := 0, 0
+
+	// write makes sure the output is long enough for n bytes at pos and returns them
+	write := func(n int) []byte {
+		if pos+n > len(out) {
+			out = append(out, make([]byte, pos+n-len(out))...)
+		}
+		b := out[pos : pos+n]
+		pos += n
+		return b
+	}
+
+	for i := 0; i < len(format); {
+		code := format[i]
+		i++
+		arg := 1
+		if i < len(format) && format[i] == '*' {
+			arg = -1
+			i++
+		} else if i < len(format) && '0' <= format[i] && format[i] <= '9' {
+			j := i
+			for j < len(format) && '0' <= format[j] && format[j] <= '9' {
+				j++
+			}
+			n, err := strconv.Atoi(format[i:j])
+			if err != nil {
+				return "", fmt.Errorf("type %c: integer overflow in format string", code)
+			}
+			arg, i = n, j
+		}
+
+		switch code {
+		case 'a', 'A', 'Z', 'h', 'H':
+			if current >= len(args) {
+				return "", fmt.Errorf("type %c: not enough arguments", code)
+			}
+			str := toString(args[current])
+			current++
+			if arg < 0 {
+				arg = len(str)
+				if code == 'Z' {
+					arg++
+				}
+			}
+
+			if code == 'h' || code == 'H' {
+				if arg > len(str) {
+					arg = len(str)
+				}
+				shift := uint(4)
+				if code == 'h' {
+					shift = 0
+				}
+				var b []byte
+				for k := 0; k < arg; k++ {
+					if k%2 == 0 {
+						b = write(1)
+						b[0] = 0
+					}
+					b[0] |= hexNibble(str[k]) << shift
+					shift = (shift + 4) & 7
+				}
+				continue
+			}
+
+			b := write(arg)
+			pad := byte(0)
+			if code == 'A' {
+				pad = ' '
+			}
+			for k := range b {
+				b[k] = pad
+			}
+			n := arg
+			if code == 'Z' && n > 0 {
+				n--
+			}
+			copy(b[:n], str)
+		case 'c', 'C', 's', 'S', 'n', 'v', 'i', 'I', 'l', 'L', 'N', 'V', 'q', 'Q', 'J', 'P', 'f', 'g', 'G', 'd', 'e', 'E':
+			if arg < 0 {
+				arg = len(args) - current
+			}
+			if current+arg > len(args) {
+				return "", fmt.Errorf("type %c: too few arguments", code)
+			}
+			for ; arg > 0; arg-- {
+				v := args[current]
+				current++
+				switch code {
+				case 'c', 'C':
+					write(1)[0] = byte(toInt(v))
+				case 's', 'S', 'v':
+					binary.LittleEndian.PutUint16(write(2), uint16(toInt(v)))
+				case 'n':
+					binary.BigEndian.PutUint16(write(2), uint16(toInt(v)))
+				case 'i', 'I', 'l', 'L', 'V':
+					binary.LittleEndian.PutUint32(write(4), uint32(toInt(v)))
+				case 'N':
+					binary.BigEndian.PutUint32(write(4), uint32(toInt(v)))
+				case 'q', 'Q', 'P':
+					binary.LittleEndian.PutUint64(write(8), uint64(toInt(v)))
+				case 'J':
+					binary.BigEndian.PutUint64(write(8), uint64(toInt(v)))
+				case 'f', 'g':
+					binary.LittleEndian.PutUint32(write(4), math.Float32bits(float32(toFloat(v))))
+				case 'G':
+					binary.BigEndian.PutUint32(write(4), math.Float32bits(float32(toFloat(v))))
+				case 'd', 'e':
+					binary.LittleEndian.PutUint64(write(8), math.Float64bits(toFloat(v)))
+				case 'E':
+					binary.BigEndian.PutUint64(write(8), math.Float64bits(toFloat(v)))
+				}
+			}
+		case 'x':
+			if arg < 0 {
+				arg = 1
+			}
+			b := write(arg)
+			for k := range b {
+				b[k] = 0
+			}
+		case 'X':
+			if arg < 0 {
+				arg = 1
+			}
+			if pos -= arg; pos < 0 {
+				pos = 0
+			}
+		case '@':
+			if arg < 0 {
+				arg = 1
+			}
+			if arg > pos {
+				b := write(arg - pos)
+				for k := range b {
+					b[k] = 0
+				}
+			}
+			pos = arg
+		default:
+			return "", fmt.Errorf("type %c: unknown format code", code)
+		}
+	}
+	return string(out[:pos]), nil
+}
+
+// hexNibble returns the value of a hex digit, 0 for the illegal digits like PHP
+func hexNibble(c byte) byte {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0'
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10
+	}
+	return 0
+}
+
+// Unpack unpack data from binary string
+//
+// The format codes are the same as Pack, each one is followed by an optional repeater and name,
+// and separated by "/", like "Nlen/a*data". The keys of the result are the names, numbered from 1
+// if there is a repeater or no name, like PHP. Integers are int, floats are float64 and the others
+// are strings. The optional offset is where to start unpacking from.
+// .eg Unpack("nid/C2flag", "\x00\x01\x02\x03") returns {"id": 1, "flag1": 2, "flag2": 3}
+//
+// see http://php.net/manual/en/function.unpack.php
+func Unpack(format, data string, offset ...int) (map[string]interface{}, error) {
+	if len(offset) > 0 {
+		if offset[0] < 0 || offset[0] > len(data) {
+			return nil, errors.New("offset must be contained in argument data")
+		}
+		data = data[offset[0]:]
+	}
+
+	buf := []byte(data)
+	res := make(map[string]interface{})
+	pos := 0
+	for i := 0; i < len(format); i++ {
+		code := format[i]
+		i++
+		repetitions := 1
+		if i < len(format) && format[i] == '*' {
+			repetitions = -1
+			i++
+		} else if i < len(format) && '0' <= format[i] && format[i] <= '9' {
+			j := i
+			for j < len(format) && '0' <= format[j] && format[j] <= '9' {
+				j++
+			}
+			n, err := strconv.Atoi(format[i:j])
+			if err != nil || n > math.MaxInt32 {
+				return nil, fmt.Errorf("type %c: integer overflow in format string", code)
+			}
+			repetitions, i = n, j
+		}
+		start := i
+		for i < len(format) && format[i] != '/' {
+			i++
+		}
+		name := format[start:i]
+		if len(name) > 200 {
+			name = name[:200]
+		}
+		count := repetitions
+
+		size := 0
+		switch code {
+		case 'X', '@':
+			if repetitions < 0 {
+				repetitions = 1
+			}
+		case 'a', 'A', 'Z':
+			size, repetitions = repetitions, 1
+		case 'h', 'H':
+			size = repetitions
+			if repetitions > 0 {
+				size = (repetitions + 1) / 2
+			}
+			repetitions = 1
+		case 'c', 'C', 'x':
+			size = 1
+		case 's', 'S', 'n', 'v':
+			size = 2
+		case 'i', 'I', 'l', 'L', 'N', 'V', 'f', 'g', 'G':
+			size = 4
+		case 'q', 'Q', 'J', 'P', 'd', 'e', 'E':
+			size = 8
+		default:
+			return nil, fmt.Errorf("invalid format type %c", code)
+		}
+
+		for k := 0; k != repetitions; k++ {
+			if code == 'X' || code == '@' {
+				if code == '@' {
+					if repetitions <= len(data) {
+						pos = repetitions
+					}
+					break
+				}
+				if pos--; pos < 0 {
+					pos = 0
+					break
+				}
+				continue
+			}
+
+			if pos+size > len(data) {
+				if repetitions < 0 {
+					break
+				}
+				return nil, fmt.Errorf("type %c: not enough input, need %d, have %d", code, size, len(data)-pos)
+			}
+
+			key := name
+			if repetitions != 1 || name == "" {
+				key = name + strconv.Itoa(k+1)
+			}
+
+			in := buf[pos:]
+			switch code {
+			case 'a', 'A', 'Z':
+				n := len(in)
+				if size >= 0 && n > size {
+					n = size
+				}
+				size = n
+				v := in[:n]
+				switch code {
+				case 'A':
+					for n > 0 && (v[n-1] == 0 || v[n-1] == ' ' || v[n-1] == '\t' || v[n-1] == '\r' || v[n-1] == '\n') {
+						n--
+					}
+					v = v[:n]
+				case 'Z':
+					for s := 0; s < n; s++ {
+						if v[s] == 0 {
+							v = v[:s]
+							break
+						}
+					}
+				}
+				res[key] = string(v)
+			case 'h', 'H':
+				n := len(in) * 2
+				if size >= 0 && n > size*2 {
+					n = size * 2
+				}
+				if n > 0 && count > 0 {
+					n -= count % 2
+				}
+				shift := uint(4)
+				if code == 'h' {
+					shift = 0
+				}
+				const digits = "0123456789abcdef"
+				b := make([]byte, n)
+				for o := 0; o < n; o++ {
+					b[o] = digits[in[o/2]>>shift&0xf]
+					shift = (shift + 4) & 7
+				}
+				if count < 0 {
+					size = len(in)
+				}
+				res[key] = string(b)
+			case 'c':
+				res[key] = int(int8(in[0]))
+			case 'C':
+				res[key] = int(in[0])
+			case 's':
+				res[key] = int(int16(binary.LittleEndian.Uint16(in)))
+			case 'S', 'v':
+				res[key] = int(binary.LittleEndian.Uint16(in))
+			case 'n':
+				res[key] = int(binary.BigEndian.Uint16(in))
+			case 'i', 'l':
+				res[key] = int(int32(binary.LittleEndian.Uint32(in)))
+			case 'I', 'L', 'V':
+				res[key] = int(binary.LittleEndian.Uint32(in))
+			case 'N':
+				res[key] = int(binary.BigEndian.Uint32(in))
+			case 'q', 'Q', 'P':
+				res[key] = int(binary.LittleEndian.Uint64(in))
+			case 'J':
+				res[key] = int(binary.BigEndian.Uint64(in))
+			case 'f', 'g':
+				res[key] = float64(math.Float32frombits(binary.LittleEndian.Uint32(in)))
+			case 'G':
+				res[key] = float64(math.Float32frombits(binary.BigEndian.Uint32(in)))
+			case 'd', 'e':
+				res[key] = math.Float64frombits(binary.LittleEndian.Uint64(in))
+			case 'E':
+				res[key] = math.Float64frombits(binary.BigEndian.Uint64(in))
+			}
+			pos += size
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,205 @@
+package php
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Algorithms of PasswordHash, the same as PHP's PASSWORD_* constants
+const (
+	// PasswordDefault is the default algorithm, bcrypt
+	PasswordDefault string = "2y"
+	// PasswordBcrypt creates "$2y$" hashes with the CRYPT_BLOWFISH algorithm
+	PasswordBcrypt string = "2y"
+	// PasswordArgon2i creates "$argon2i$" hashes
+	PasswordArgon2i string = "argon2i"
+	// PasswordArgon2id creates "$argon2id$" hashes
+	PasswordArgon2id string = "argon2id"
+)
+
+const (
+	// PasswordBcryptDefaultCost is the default "cost" of bcrypt, the same as PHP 8.4
+	PasswordBcryptDefaultCost int = 12
+	// PasswordArgon2DefaultMemoryCost is the default "memory_cost" of argon2 in KiB
+	PasswordArgon2DefaultMemoryCost int = 65536
+	// PasswordArgon2MaxMemoryCost is the largest "memory_cost" of argon2 in KiB, 4 GiB
+	PasswordArgon2MaxMemoryCost int = 4 << 20
+	// PasswordArgon2DefaultTimeCost is the default "time_cost" of argon2
+	PasswordArgon2DefaultTimeCost int = 4
+	// PasswordArgon2DefaultThreads is the default "threads" of argon2
+	PasswordArgon2DefaultThreads int = 1
+
+	// bcryptMaxPassword is the length bcrypt truncates passwords to
+	bcryptMaxPassword = 72
+	argon2SaltLength  = 16
+	argon2HashLength  = 32
+)
+
+// PasswordInfo is the information about a hash returned by PasswordGetInfo
+type PasswordInfo struct {
+	Algo     string // one of the Password* algorithms, empty string if it's unknown
+	AlgoName string // "bcrypt", "argon2i", "argon2id" or "unknown"
+	Options  map[string]int
+}
+
+// PasswordHash creates a password hash
+//
+// The hashes are the same as PHP's password_hash, options can be {"cost": 12} for bcrypt, and
+// {"memory_cost": 65536, "time_cost": 4, "threads": 1} for argon2. The cost of bcrypt must be
+// between 4 and 31, the memory_cost of argon2 at most PasswordArgon2MaxMemoryCost. Like PHP,
+// bcrypt only uses the first 72 bytes of password.
+// .eg PasswordHash("secret", PasswordDefault, map[string]int{"cost": 11})
+//
+// see http://php.net/manual/en/function.password-hash.php
+func PasswordHash(password, algo string, options ...map[string]int) (string, error) {
+	opts := passwordOptions(algo, options...)
+	switch algo {
+	case PasswordBcrypt, "":
+		if opts["cost"] < bcrypt.MinCost || opts["cost"] > bcrypt.MaxCost {
+			return "", fmt.Errorf("invalid bcrypt cost parameter specified: %d", opts["cost"])
+		}
+		if len(password) > bcryptMaxPassword {
+			password = password[:bcryptMaxPassword]
+		}
+		h, err := bcrypt.GenerateFromPassword([]byte(password), opts["cost"])
+		if err != nil {
+			return "", err
+		}
+		// $2a$ and $2y$ are the same algorithm, PHP writes $2y$
+		return "$2y$" + string(h[4:]), nil
+	case PasswordArgon2i, PasswordArgon2id:
+		if opts["memory_cost"] < 8*opts["threads"] || opts["memory_cost"] > PasswordArgon2MaxMemoryCost || opts["time_cost"] < 1 || opts["threads"] < 1 || opts["threads"] > 255 {
+			return "", fmt.Errorf("invalid options of %s", algo)
+		}
+		salt := make([]byte, argon2SaltLength)
+		if _, err := rand.Read(salt); err != nil {
+			return "", err
+		}
+		key := argon2Key(algo, []byte(password), salt, opts, argon2HashLength)
+		return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s", algo, argon2.Version, opts["memory_cost"], opts["time_cost"], opts["threads"],
+			base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+	}
+	return "", fmt.Errorf("unknown password hashing algorithm: %s", algo)
+}
+
+// PasswordVerify verifies that a password matches a hash
+//
+// It accepts the bcrypt ($2y$, $2a$, $2b$) and argon2 hashes created by PHP and PasswordHash,
+// the comparison is in constant time. An argon2 hash with a memory_cost above
+// PasswordArgon2MaxMemoryCost never matches, so a forged hash can't allocate unbounded memory.
+//
+// see http://php.net/manual/en/function.password-verify.php
+func PasswordVerify(password, hash string) bool {
+	info := PasswordGetInfo(hash)
+	switch info.Algo {
+	case PasswordBcrypt:
+		if len(password) > bcryptMaxPassword {
+			password = password[:bcryptMaxPassword]
+		}
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+	case PasswordArgon2i, PasswordArgon2id:
+		parts := strings.Split(hash, "$")
+		salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+		if err != nil {
+			return false
+		}
+		want, err := base64.RawStdEncoding.DecodeString(parts[5])
+		if err != nil || len(want) == 0 {
+			return false
+		}
+		key := argon2Key(info.Algo, []byte(password), salt, info.Options, uint32(len(want)))
+		return subtle.ConstantTimeCompare(key, want) == 1
+	}
+	return false
+}
+
+// PasswordNeedsRehash checks if the given hash matches the given algorithm and options
+//
+// see http://php.net/manual/en/function.password-needs-rehash.php
+func PasswordNeedsRehash(hash, algo string, options ...map[string]int) bool {
+	info := PasswordGetInfo(hash)
+	if info.Algo == "" || info.Algo != algo {
+		return true
+	}
+	for k, v := range passwordOptions(algo, options...) {
+		if info.Options[k] != v {
+			return true
+		}
+	}
+	return false
+}
+
+// PasswordGetInfo returns information about the given hash
+//
+// An argon2 hash with a memory_cost above PasswordArgon2MaxMemoryCost is unknown.
+//
+// see http://php.net/manual/en/function.password-get-info.php
+func PasswordGetInfo(hash string) PasswordInfo {
+	unknown := PasswordInfo{AlgoName: "unknown", Options: map[string]int{}}
+
+	if len(hash) == 60 && hash[0] == '$' && hash[1] == '2' && strings.IndexByte("aby", hash[2]) >= 0 && hash[3] == '$' {
+		cost, err := bcrypt.Cost([]byte(hash))
+		if err != nil {
+			return unknown
+		}
+		return PasswordInfo{Algo: PasswordBcrypt, AlgoName: "bcrypt", Options: map[string]int{"cost": cost}}
+	}
+
+	// $argon2id$v=19$m=65536,t=4,p=1$salt$hash
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[0] != "" || (parts[1] != PasswordArgon2i && parts[1] != PasswordArgon2id) {
+		return unknown
+	}
+	var version, m, t, p int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return unknown
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &m, &t, &p); err != nil || m < 1 || m > PasswordArgon2MaxMemoryCost || t < 1 || p < 1 || p > 255 {
+		return unknown
+	}
+	return PasswordInfo{
+		Algo:     parts[1],
+		AlgoName: parts[1],
+		Options:  map[string]int{"memory_cost": m, "time_cost": t, "threads": p},
+	}
+}
+
+// passwordOptions fills the default options of algo
+func passwordOptions(algo string, options ...map[string]int) map[string]int {
+	var opts map[string]int
+	switch algo {
+	case PasswordBcrypt, "":
+		opts = map[string]int{"cost": PasswordBcryptDefaultCost}
+	case PasswordArgon2i, PasswordArgon2id:
+		opts = map[string]int{
+			"memory_cost": PasswordArgon2DefaultMemoryCost,
+			"time_cost":   PasswordArgon2DefaultTimeCost,
+			"threads":     PasswordArgon2DefaultThreads,
+		}
+	default:
+		return map[string]int{}
+	}
+	if len(options) > 0 {
+		for k := range opts {
+			if v, ok := options[0][k]; ok {
+				opts[k] = v
+			}
+		}
+	}
+	return opts
+}
+
+// argon2Key derives the argon2i or argon2id key
+func argon2Key(algo string, password, salt []byte, opts map[string]int, keyLen uint32) []byte {
+	t, m, p := uint32(opts["time_cost"]), uint32(opts["memory_cost"]), uint8(opts["threads"])
+	if algo == PasswordArgon2i {
+		return argon2.Key(password, salt, t, m, p, keyLen)
+	}
+	return argon2.IDKey(password, salt, t, m, p, keyLen)
+}
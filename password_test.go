@@ -0,0 +1,50 @@
+package php
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPasswordHashBcryptCost(t *testing.T) {
+	for _, cost := range []int{-1, 0, 3, 32} {
+		if h, err := PasswordHash("secret", PasswordBcrypt, map[string]int{"cost": cost}); err == nil {
+			t.Errorf("PasswordHash with cost %d = %q, want an error", cost, h)
+		}
+	}
+
+	h, err := PasswordHash("secret", PasswordBcrypt, map[string]int{"cost": 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info := PasswordGetInfo(h); info.Options["cost"] != 4 {
+		t.Errorf("cost of %q = %d, want 4", h, info.Options["cost"])
+	}
+	if !PasswordVerify("secret", h) {
+		t.Errorf("PasswordVerify(%q) = false", h)
+	}
+}
+
+func TestPasswordArgon2MemoryCost(t *testing.T) {
+	opts := map[string]int{"memory_cost": 64, "time_cost": 1, "threads": 1}
+	h, err := PasswordHash("secret", PasswordArgon2id, opts)
+	if err != nil || !PasswordVerify("secret", h) {
+		t.Fatalf("PasswordHash = %q, %v", h, err)
+	}
+	if info := PasswordGetInfo(h); info.Algo != PasswordArgon2id || info.Options["memory_cost"] != 64 {
+		t.Errorf("PasswordGetInfo(%q) = %v", h, info)
+	}
+
+	opts["memory_cost"] = PasswordArgon2MaxMemoryCost + 1
+	if h, err := PasswordHash("secret", PasswordArgon2id, opts); err == nil {
+		t.Errorf("PasswordHash with memory_cost %d = %q, want an error", opts["memory_cost"], h)
+	}
+
+	// a stored hash above the limit is rejected before deriving the key
+	forged := strings.Replace(h, "m=64,", "m=4294967295,", 1)
+	if info := PasswordGetInfo(forged); info.Algo != "" {
+		t.Errorf("PasswordGetInfo(%q) = %v, want unknown", forged, info)
+	}
+	if PasswordVerify("secret", forged) {
+		t.Errorf("PasswordVerify(%q) = true", forged)
+	}
+}
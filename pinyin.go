@@ -0,0 +1,217 @@
+package php
+
+import (
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// styles of Pinyin
+const (
+	PinyinTone        = iota // zhōng guó
+	PinyinToneNumber         // zhong1 guo2
+	PinyinNormal             // zhong guo
+	PinyinFirstLetter        // z g
+)
+
+// Pinyin convert Chinese characters to pinyin
+//
+// The result holds one element per character of str like Strlen counts them, so res[i] is the
+// readings of Substr(str, i, 1), characters which are not in the dictionary get an empty slice. The characters
+// of the longest known phrase get their readings in the phrase, the others their default reading.
+// All the readings are returned if heteronym is true, the one in context first. "ü" is written "v"
+// in the styles without tone marks and the neutral tone has no number.
+// .eg Pinyin("中国", PinyinTone) returns [[zhōng] [guó]], Pinyin("重庆", PinyinTone) returns [[chóng] [qìng]],
+// Pinyin("重a", PinyinToneNumber, true) returns [[zhong4 chong2 tong2] []]
+func Pinyin(str string, style int, heteronym ...bool) [][]string {
+	context := pinyinReadings(str)
+	res := make([][]string, 0, len(context))
+	k := 0
+	for _, r := range str {
+		reading := context[k]
+		k++
+		if reading == "" {
+			res = append(res, []string{})
+			continue
+		}
+		list := []string{pinyinStyle(reading, style)}
+		if len(heteronym) == 0 || !heteronym[0] {
+			res = append(res, list)
+			continue
+		}
+		for _, reading := range strings.Split(pinyinDict[r], ",") {
+			if reading = pinyinStyle(reading, style); !InArray(reading, list) {
+				list = append(list, reading)
+			}
+		}
+		res = append(res, list)
+	}
+	return res
+}
+
+// PinyinString convert the Chinese characters of str to pinyin with their readings in context like Pinyin
+//
+// The readings and the runs of other characters in between are joined with separator, " " by default,
+// the other characters are kept as they are but the spaces around them are trimmed.
+// .eg PinyinString("我爱Go语言", PinyinNormal) returns "wo ai Go yu yan", PinyinString("重庆银行", PinyinTone) returns "chóng qìng yín háng",
+// PinyinString("中华人民共和国", PinyinFirstLetter, "") returns "zhrmghg"
+func PinyinString(str string, style int, separator ...string) string {
+	sep := " "
+	if len(separator) > 0 {
+		sep = separator[0]
+	}
+	context := pinyinReadings(str)
+	var words []string
+	other := -1
+	flush := func(end int) {
+		if other >= 0 {
+			if word := strings.TrimSpace(str[other:end]); word != "" {
+				words = append(words, word)
+			}
+			other = -1
+		}
+	}
+	k := 0
+	for i := range str {
+		if reading := context[k]; reading != "" {
+			flush(i)
+			words = append(words, pinyinStyle(reading, style))
+		} else if other < 0 {
+			other = i
+		}
+		k++
+	}
+	flush(len(str))
+	return strings.Join(words, sep)
+}
+
+// Slug generate a URL friendly slug from str
+//
+// Accented letters are transliterated to ASCII, Chinese characters are converted to pinyin without
+// tones and everything is lower cased. Apostrophes are removed and any other characters separate
+// words, which are joined with separator, "-" by default.
+// .eg Slug("Héllo Wörld, don't PANIC!") returns "hello-world-dont-panic", Slug("中文 Straße", "_") returns "zhong_wen_strasse"
+func Slug(str string, separator ...string) string {
+	sep := "-"
+	if len(separator) > 0 {
+		sep = separator[0]
+	}
+	var b strings.Builder
+	pending := false
+	write := func(s string) {
+		for i := 0; i < len(s); i++ {
+			c := s[i]
+			switch {
+			case c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9':
+				if pending && b.Len() > 0 {
+					b.WriteString(sep)
+				}
+				pending = false
+				if c >= 'A' && c <= 'Z' {
+					c += 'a' - 'A'
+				}
+				b.WriteByte(c)
+			case c != '\'':
+				pending = true
+			}
+		}
+	}
+	context := pinyinReadings(str)
+	k := 0
+	for _, r := range str {
+		reading := context[k]
+		k++
+		switch {
+		case r < 0x80:
+			write(string(r))
+		case reading != "":
+			pending = true
+			write(pinyinStyle(reading, PinyinNormal))
+			pending = true
+		default:
+			if t := transliterate(r); t != "" {
+				write(t)
+			} else {
+				pending = true
+			}
+		}
+	}
+	return b.String()
+}
+
+var (
+	pinyinPhraseOnce     sync.Once
+	pinyinPhraseTrie     *trie
+	pinyinPhraseReadings [][]string
+)
+
+// pinyinReadings returns the reading of every character of str like Strlen counts them, the longest
+// phrase of pinyinPhrases is matched from left to right and the other characters get their default
+// reading, "" if they are not in the dictionary
+func pinyinReadings(str string) []string {
+	pinyinPhraseOnce.Do(func() {
+		pinyinPhraseTrie = newTrie()
+		for phrase, readings := range pinyinPhrases {
+			pinyinPhraseTrie.insert(phrase, len(pinyinPhraseReadings))
+			pinyinPhraseReadings = append(pinyinPhraseReadings, strings.Split(readings, " "))
+		}
+	})
+
+	res := make([]string, 0, len(str)/3)
+	for i := 0; i < len(str); {
+		if v, n := pinyinPhraseTrie.longest(str[i:]); v >= 0 {
+			res = append(res, pinyinPhraseReadings[v]...)
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(str[i:])
+		res = append(res, pinyinDefault(pinyinDict[r]))
+		i += size
+	}
+	return res
+}
+
+// pinyinDefault returns the default reading of the comma separated readings of the dictionary
+func pinyinDefault(readings string) string {
+	if i := strings.IndexByte(readings, ','); i >= 0 {
+		return readings[:i]
+	}
+	return readings
+}
+
+// pinyinStyle converts reading which has tone marks to style
+func pinyinStyle(reading string, style int) string {
+	if style == PinyinTone {
+		return reading
+	}
+	b := make([]byte, 0, len(reading)+1)
+	tone := byte(0)
+	for _, r := range norm.NFD.String(reading) {
+		switch r {
+		case '\u0304':
+			tone = '1'
+		case '\u0301':
+			tone = '2'
+		case '\u030C':
+			tone = '3'
+		case '\u0300':
+			tone = '4'
+		case '\u0308':
+			// ü is decomposed into u and the diaeresis
+			b[len(b)-1] = 'v'
+		case '\u0302':
+			// ê is written e
+		default:
+			b = append(b, string(r)...)
+		}
+	}
+	switch {
+	case style == PinyinFirstLetter:
+		return string(b[:1])
+	case style == PinyinToneNumber && tone != 0:
+		b = append(b, tone)
+	}
+	return string(b)
+}
@@ -0,0 +1,170 @@
+package php
+
+// pinyinPhrases holds the readings of common phrases with characters which have several readings,
+// they are written like phrase-pinyin-data of https://github.com/mozillazg/phrase-pinyin-data
+// (MIT License, see LICENSE-pinyin-data). The readings are separated with a space, one per character.
+var pinyinPhrases = map[string]string{
+	"重庆":   "chóng qìng",
+	"重复":   "chóng fù",
+	"重新":   "chóng xīn",
+	"重叠":   "chóng dié",
+	"重阳":   "chóng yáng",
+	"重量":   "zhòng liàng",
+	"重要":   "zhòng yào",
+	"重担":   "zhòng dàn",
+	"银行":   "yín háng",
+	"行长":   "háng zhǎng",
+	"行业":   "háng yè",
+	"行情":   "háng qíng",
+	"同行":   "tóng háng",
+	"外行":   "wài háng",
+	"内行":   "nèi háng",
+	"排行":   "pái háng",
+	"行为":   "xíng wéi",
+	"行人":   "xíng rén",
+	"长大":   "zhǎng dà",
+	"成长":   "chéng zhǎng",
+	"校长":   "xiào zhǎng",
+	"市长":   "shì zhǎng",
+	"长江":   "cháng jiāng",
+	"长城":   "cháng chéng",
+	"音乐":   "yīn yuè",
+	"乐器":   "yuè qì",
+	"快乐":   "kuài lè",
+	"还是":   "hái shì",
+	"还有":   "hái yǒu",
+	"归还":   "guī huán",
+	"还原":   "huán yuán",
+	"还款":   "huán kuǎn",
+	"觉得":   "jué de",
+	"睡觉":   "shuì jiào",
+	"午觉":   "wǔ jiào",
+	"的确":   "dí què",
+	"目的":   "mù dì",
+	"得到":   "dé dào",
+	"会计":   "kuài jì",
+	"会议":   "huì yì",
+	"朝代":   "cháo dài",
+	"朝鲜":   "cháo xiǎn",
+	"朝气":   "zhāo qì",
+	"曾经":   "céng jīng",
+	"人参":   "rén shēn",
+	"参加":   "cān jiā",
+	"参差":   "cēn cī",
+	"单于":   "chán yú",
+	"单位":   "dān wèi",
+	"都市":   "dū shì",
+	"首都":   "shǒu dū",
+	"大夫":   "dài fu",
+	"仿佛":   "fǎng fú",
+	"佛教":   "fó jiào",
+	"供给":   "gōng jǐ",
+	"给予":   "jǐ yǔ",
+	"供应":   "gōng yìng",
+	"处理":   "chǔ lǐ",
+	"处分":   "chǔ fèn",
+	"到处":   "dào chù",
+	"好处":   "hǎo chù",
+	"传记":   "zhuàn jì",
+	"传说":   "chuán shuō",
+	"调查":   "diào chá",
+	"调整":   "tiáo zhěng",
+	"空调":   "kōng tiáo",
+	"强调":   "qiáng diào",
+	"倔强":   "jué jiàng",
+	"勉强":   "miǎn qiǎng",
+	"差别":   "chā bié",
+	"出差":   "chū chāi",
+	"数学":   "shù xué",
+	"数据":   "shù jù",
+	"少数":   "shǎo shù",
+	"便宜":   "pián yi",
+	"方便":   "fāng biàn",
+	"应该":   "yīng gāi",
+	"应用":   "yìng yòng",
+	"答应":   "dā ying",
+	"回答":   "huí dá",
+	"头发":   "tóu fa",
+	"理发":   "lǐ fà",
+	"发现":   "fā xiàn",
+	"干净":   "gān jìng",
+	"干部":   "gàn bù",
+	"能干":   "néng gàn",
+	"几乎":   "jī hū",
+	"茶几":   "chá jī",
+	"薄荷":   "bò he",
+	"薄弱":   "bó ruò",
+	"暴露":   "bào lù",
+	"露面":   "lòu miàn",
+	"血液":   "xuè yè",
+	"着急":   "zháo jí",
+	"睡着":   "shuì zháo",
+	"着手":   "zhuó shǒu",
+	"穿着":   "chuān zhuó",
+	"恶心":   "ě xīn",
+	"恶劣":   "è liè",
+	"可恶":   "kě wù",
+	"厌恶":   "yàn wù",
+	"结果":   "jié guǒ",
+	"结实":   "jiē shi",
+	"角色":   "jué sè",
+	"主角":   "zhǔ jué",
+	"角度":   "jiǎo dù",
+	"模样":   "mú yàng",
+	"模型":   "mó xíng",
+	"宿舍":   "sù shè",
+	"舍得":   "shě de",
+	"似的":   "shì de",
+	"相似":   "xiāng sì",
+	"反省":   "fǎn xǐng",
+	"省略":   "shěng lüè",
+	"率领":   "shuài lǐng",
+	"效率":   "xiào lǜ",
+	"概率":   "gài lǜ",
+	"丢三落四": "diū sān là sì",
+	"落后":   "luò hòu",
+	"哪吒":   "né zhā",
+	"尽管":   "jǐn guǎn",
+	"尽力":   "jìn lì",
+	"将军":   "jiāng jūn",
+	"关卡":   "guān qiǎ",
+	"卡片":   "kǎ piàn",
+	"扁担":   "biǎn dan",
+	"担任":   "dān rèn",
+	"高兴":   "gāo xìng",
+	"兴趣":   "xìng qù",
+	"兴奋":   "xīng fèn",
+	"中奖":   "zhòng jiǎng",
+	"命中":   "mìng zhòng",
+	"种子":   "zhǒng zi",
+	"种类":   "zhǒng lèi",
+	"种植":   "zhòng zhí",
+	"剥削":   "bō xuē",
+	"亲戚":   "qīn qi",
+	"亲家":   "qìng jia",
+	"了解":   "liǎo jiě",
+	"为了":   "wèi le",
+	"因为":   "yīn wèi",
+	"作为":   "zuò wéi",
+	"认为":   "rèn wéi",
+	"灾难":   "zāi nàn",
+	"难民":   "nàn mín",
+	"暖和":   "nuǎn huo",
+	"附和":   "fù hè",
+	"淹没":   "yān mò",
+	"沉没":   "chén mò",
+	"背包":   "bēi bāo",
+	"背景":   "bèi jǐng",
+	"贝壳":   "bèi ké",
+	"地壳":   "dì qiào",
+	"系鞋带":  "jì xié dài",
+	"西藏":   "xī zàng",
+	"宝藏":   "bǎo zàng",
+	"收藏":   "shōu cáng",
+	"弹琴":   "tán qín",
+	"子弹":   "zǐ dàn",
+	"更加":   "gèng jiā",
+	"更新":   "gēng xīn",
+	"看守":   "kān shǒu",
+	"银行卡":  "yín háng kǎ",
+}
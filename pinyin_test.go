@@ -0,0 +1,65 @@
+package php
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPinyinPhrases(t *testing.T) {
+	tests := []struct {
+		str   string
+		style int
+		want  string
+	}{
+		{"重庆银行", PinyinTone, "chóng qìng yín háng"},
+		{"重要", PinyinTone, "zhòng yào"},
+		{"银行行长", PinyinTone, "yín háng háng zhǎng"},
+		{"我在重庆银行工作", PinyinToneNumber, "wo3 zai4 chong2 qing4 yin2 hang2 gong1 zuo4"},
+		{"丢三落四", PinyinNormal, "diu san la si"},
+		{"长城和长江", PinyinTone, "cháng chéng hé cháng jiāng"},
+		{"我的大夫", PinyinToneNumber, "wo3 de dai4 fu"},
+		{"去重庆a", PinyinFirstLetter, "q c q a"},
+		{"重", PinyinTone, "zhòng"},
+	}
+	for _, test := range tests {
+		if got := PinyinString(test.str, test.style); got != test.want {
+			t.Errorf("PinyinString(%q, %d) = %q, want %q", test.str, test.style, got, test.want)
+		}
+	}
+
+	if got, want := Pinyin("重庆a", PinyinTone), [][]string{{"chóng"}, {"qìng"}, {}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Pinyin = %q, want %q", got, want)
+	}
+	if got, want := Pinyin("重a", PinyinToneNumber, true), [][]string{{"zhong4", "chong2", "tong2"}, {}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Pinyin heteronym = %q, want %q", got, want)
+	}
+	if got := Pinyin("重庆", PinyinNormal, true); len(got) != 2 || got[0][0] != "chong" || !InArray("zhong", got[0]) {
+		t.Errorf("Pinyin heteronym = %q", got)
+	}
+	if got, want := Slug("重庆 Bank"), "chong-qing-bank"; got != want {
+		t.Errorf("Slug = %q, want %q", got, want)
+	}
+}
+
+func TestPinyinPhraseDict(t *testing.T) {
+	for phrase := range pinyinPhrases {
+		got := pinyinReadings(phrase)
+		if len(got) != Strlen(phrase) {
+			t.Errorf("%s has %d readings, want %d", phrase, len(got), Strlen(phrase))
+			continue
+		}
+		for i, r := range []rune(phrase) {
+			// a reading in a phrase is one of the character or its neutral tone
+			found := false
+			for _, reading := range strings.Split(pinyinDict[r], ",") {
+				if got[i] == reading || got[i] == pinyinStyle(reading, PinyinNormal) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("%s: %c is read %q, not one of %q", phrase, r, got[i], pinyinDict[r])
+			}
+		}
+	}
+}
@@ -0,0 +1,758 @@
+package php
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Flags of PregMatchAll, PregMatchAllOffset, PregSplit and PregSplitOffset
+const (
+	// PregPatternOrder orders results so that result[0] is an array of full pattern matches,
+	// result[1] is an array of strings matched by the first parenthesized subpattern, and so on
+	PregPatternOrder int = 1
+	// PregSetOrder orders results so that result[0] is an array of first set of matches,
+	// result[1] is an array of second set of matches, and so on
+	PregSetOrder int = 2
+
+	// PregSplitNoEmpty only returns non-empty pieces
+	PregSplitNoEmpty int = 1
+	// PregSplitDelimCapture returns parenthesized expression in the delimiter pattern as well
+	PregSplitDelimCapture int = 2
+)
+
+// pregCacheSize is the number of compiled patterns kept, the same as PHP's
+const pregCacheSize = 4096
+
+var (
+	pregCache   = make(map[string]*pregPattern)
+	pregCacheMu sync.RWMutex
+)
+
+// PregMatchGroup is a captured group with its byte offset in subject, like the elements
+// PHP returns with PREG_OFFSET_CAPTURE. Offset is -1 if the group did not participate.
+type PregMatchGroup struct {
+	Text   string
+	Offset int
+}
+
+// pregPattern is a PHP pattern compiled to Go's regexp
+type pregPattern struct {
+	re      *regexp.Regexp
+	utf8    bool   // the u modifier
+	expr    string // the translated expression
+	dollars []int  // the groups added for $ before a final newline, see pregConvert
+}
+
+// PregMatch perform a regular expression match
+//
+// pattern is a PHP (PCRE) pattern with delimiters and modifiers like "/^(\w+)@/i", the modifiers
+// i, m, s, x, u, U and D are supported. Go's regexp is RE2, so lookaround, backreferences,
+// atomic groups, possessive quantifiers and recursion are reported as an error.
+// Like PHP, $ also matches before a newline at the end of subject unless the D or m modifier is given,
+// a pattern which would go on matching after such a $ is reported as an error too.
+//
+// It returns nil if there is no match, matches[0] is the text that matched the full pattern,
+// matches[1] the first captured parenthesized subpattern, and so on.
+//
+// see http://php.net/manual/en/function.preg-match.php
+func PregMatch(pattern, subject string) ([]string, error) {
+	groups, err := PregMatchOffset(pattern, subject)
+	if groups == nil {
+		return nil, err
+	}
+	return pregTexts(groups), nil
+}
+
+// PregMatchOffset is PregMatch with PREG_OFFSET_CAPTURE
+func PregMatchOffset(pattern, subject string) ([]PregMatchGroup, error) {
+	p, err := pregCompile(pattern, subject)
+	if err != nil {
+		return nil, err
+	}
+	loc := p.re.FindStringSubmatchIndex(subject)
+	if loc == nil {
+		return nil, nil
+	}
+	loc, _ = p.fix(loc)
+	return pregGroups(subject, loc, true), nil
+}
+
+// PregMatchAll perform a global regular expression match
+//
+// flags is PregPatternOrder or PregSetOrder, it's PregPatternOrder if 0
+//
+// see http://php.net/manual/en/function.preg-match-all.php
+func PregMatchAll(pattern, subject string, flags int) ([][]string, error) {
+	all, err := PregMatchAllOffset(pattern, subject, flags)
+	if err != nil {
+		return nil, err
+	}
+	res := make([][]string, len(all))
+	for i, groups := range all {
+		res[i] = pregTexts(groups)
+	}
+	return res, nil
+}
+
+// PregMatchAllOffset is PregMatchAll with PREG_OFFSET_CAPTURE
+func PregMatchAllOffset(pattern, subject string, flags int) ([][]PregMatchGroup, error) {
+	p, err := pregCompile(pattern, subject)
+	if err != nil {
+		return nil, err
+	}
+	locs, err := p.findAll(subject, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	if flags&PregSetOrder != 0 {
+		res := make([][]PregMatchGroup, len(locs))
+		for i, loc := range locs {
+			res[i] = pregGroups(subject, loc, true)
+		}
+		return res, nil
+	}
+
+	res := make([][]PregMatchGroup, p.re.NumSubexp()-len(p.dollars)+1)
+	for i := range res {
+		res[i] = make([]PregMatchGroup, 0, len(locs))
+	}
+	for _, loc := range locs {
+		for i, g := range pregGroups(subject, loc, false) {
+			res[i] = append(res[i], g)
+		}
+	}
+	return res, nil
+}
+
+// PregReplace perform a regular expression search and replace
+//
+// pattern and replacement can be a string or []string like Replace, replacement may contain
+// references of the form \n, $n or ${n} to the nth parenthesized pattern. limit is the maximum
+// possible replacements for each pattern, -1 means no limit.
+//
+// see http://php.net/manual/en/function.preg-replace.php
+func PregReplace(pattern, replacement interface{}, subject string, limit int) (string, error) {
+	aPattern, aReplace, err := buildReplaceSlice(pattern, replacement)
+	if err != nil {
+		return "", err
+	}
+	for index, pat := range aPattern {
+		r := aReplace[index]
+		subject, err = pregReplace(pat, subject, limit, func(subject string, loc []int) string {
+			return pregExpand(r, subject, loc)
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	return subject, nil
+}
+
+// PregReplaceCallback perform a regular expression search and replace using a callback
+//
+// callback is called with the matches of every replacement like PregMatch returns
+//
+// see http://php.net/manual/en/function.preg-replace-callback.php
+func PregReplaceCallback(pattern string, callback func(matches []string) string, subject string, limit int) (string, error) {
+	return pregReplace(pattern, subject, limit, func(subject string, loc []int) string {
+		return callback(pregTexts(pregGroups(subject, loc, true)))
+	})
+}
+
+// PregSplit split string by a regular expression
+//
+// limit -1 or 0 means no limit, flags is a combination of PregSplitNoEmpty and PregSplitDelimCapture
+//
+// see http://php.net/manual/en/function.preg-split.php
+func PregSplit(pattern, subject string, limit, flags int) ([]string, error) {
+	pieces, err := PregSplitOffset(pattern, subject, limit, flags)
+	if err != nil {
+		return nil, err
+	}
+	return pregTexts(pieces), nil
+}
+
+// PregSplitOffset is PregSplit with PREG_SPLIT_OFFSET_CAPTURE
+func PregSplitOffset(pattern, subject string, limit, flags int) ([]PregMatchGroup, error) {
+	p, err := pregCompile(pattern, subject)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = -1
+	}
+	noEmpty := flags&PregSplitNoEmpty != 0
+
+	var res []PregMatchGroup
+	last := 0
+	if limit != 1 {
+		locs, err := p.findAll(subject, -1)
+		if err != nil {
+			return nil, err
+		}
+		for _, loc := range locs {
+			if !noEmpty || loc[0] != last {
+				res = append(res, PregMatchGroup{subject[last:loc[0]], last})
+				if limit != -1 {
+					limit--
+				}
+			}
+			if flags&PregSplitDelimCapture != 0 {
+				for _, g := range pregGroups(subject, loc, true)[1:] {
+					if !noEmpty || g.Text != "" {
+						res = append(res, g)
+					}
+				}
+			}
+			last = loc[1]
+			if limit != -1 && limit <= 1 {
+				break
+			}
+		}
+	}
+	if !noEmpty || last < len(subject) {
+		res = append(res, PregMatchGroup{subject[last:], last})
+	}
+	return res, nil
+}
+
+// PregQuote quote regular expression characters
+//
+// The special regular expression characters are . \ + * ? [ ^ ] $ ( ) { } = ! < > | : - # and NUL,
+// the optional delimiter will also be escaped.
+//
+// see http://php.net/manual/en/function.preg-quote.php
+func PregQuote(str string, delimiter ...string) string {
+	var delim byte
+	if len(delimiter) > 0 && delimiter[0] != "" {
+		delim = delimiter[0][0]
+	}
+
+	var b strings.Builder
+	b.Grow(len(str))
+	for i := 0; i < len(str); i++ {
+		c := str[i]
+		switch {
+		case c == 0:
+			b.WriteString("\\000")
+			continue
+		case strings.IndexByte(".\\+*?[^]$(){}=!<>|:-#", c) >= 0, delim != 0 && c == delim:
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// pregReplace is a helper function for PregReplace and PregReplaceCallback
+func pregReplace(pattern, subject string, limit int, repl func(subject string, loc []int) string) (string, error) {
+	p, err := pregCompile(pattern, subject)
+	if err != nil {
+		return "", err
+	}
+	locs, err := p.findAll(subject, limit)
+	if err != nil {
+		return "", err
+	}
+	if len(locs) == 0 {
+		return subject, nil
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, loc := range locs {
+		b.WriteString(subject[last:loc[0]])
+		b.WriteString(repl(subject, loc))
+		last = loc[1]
+	}
+	b.WriteString(subject[last:])
+	return b.String(), nil
+}
+
+// pregExpand expands the references of replacement in the way of PHP's preg_replace
+func pregExpand(replacement, subject string, loc []int) string {
+	var b strings.Builder
+	var last byte
+	for i := 0; i < len(replacement); {
+		c := replacement[i]
+		if c == '\\' || c == '$' {
+			if last == '\\' {
+				// escaped, overwrite the backslash
+				s := b.String()
+				b.Reset()
+				b.WriteString(s[:len(s)-1])
+				b.WriteByte(c)
+				i++
+				last = 0
+				continue
+			}
+			if ref, n := pregBackref(replacement[i:]); n > 0 {
+				if 2*ref+1 < len(loc) && loc[2*ref] >= 0 {
+					b.WriteString(subject[loc[2*ref]:loc[2*ref+1]])
+				}
+				i += n
+				continue
+			}
+		}
+		b.WriteByte(c)
+		last = c
+		i++
+	}
+	return b.String()
+}
+
+// pregBackref parses a reference like \1, $1 or ${1}, n is the length of it or 0 if it's not a reference
+func pregBackref(s string) (ref, n int) {
+	if len(s) < 2 {
+		return 0, 0
+	}
+	brace := s[0] == '$' && s[1] == '{'
+	i := 1
+	if brace {
+		i++
+	}
+	if i >= len(s) || s[i] < '0' || s[i] > '9' {
+		return 0, 0
+	}
+	ref = int(s[i] - '0')
+	i++
+	if i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		ref = ref*10 + int(s[i]-'0')
+		i++
+	}
+	if brace {
+		if i >= len(s) || s[i] != '}' {
+			return 0, 0
+		}
+		i++
+	}
+	return ref, i
+}
+
+// pregGroups converts the index pairs of a match, trailing groups which did not participate
+// are dropped if trim is true like PHP does
+func pregGroups(subject string, loc []int, trim bool) []PregMatchGroup {
+	n := len(loc) / 2
+	if trim {
+		for n > 1 && loc[2*n-2] < 0 {
+			n--
+		}
+	}
+	groups := make([]PregMatchGroup, n)
+	for i := range groups {
+		if loc[2*i] < 0 {
+			groups[i] = PregMatchGroup{"", -1}
+		} else {
+			groups[i] = PregMatchGroup{subject[loc[2*i]:loc[2*i+1]], loc[2*i]}
+		}
+	}
+	return groups
+}
+
+// pregTexts returns the texts of the groups
+func pregTexts(groups []PregMatchGroup) []string {
+	res := make([]string, len(groups))
+	for i, g := range groups {
+		res[i] = g.Text
+	}
+	return res
+}
+
+// fix removes the groups added for $ from the index pairs of a match, and if one of them matched
+// the final newline it moves the offsets after it before it, since PCRE's $ doesn't consume it
+func (p *pregPattern) fix(loc []int) (res []int, moved bool) {
+	if len(p.dollars) == 0 {
+		return loc, false
+	}
+	end := -1
+	for _, g := range p.dollars {
+		if loc[2*g] >= 0 {
+			end = loc[2*g]
+			break
+		}
+	}
+	res = make([]int, 0, len(loc)-2*len(p.dollars))
+	for i, g := 0, 0; i < len(loc)/2; i++ {
+		if g < len(p.dollars) && p.dollars[g] == i {
+			g++
+			continue
+		}
+		start, stop := loc[2*i], loc[2*i+1]
+		if end >= 0 && stop > end {
+			stop = end
+			if start > end {
+				start = end
+			}
+		}
+		res = append(res, start, stop)
+	}
+	return res, end >= 0
+}
+
+// findAll returns the index pairs of at most n matches, all of them if n < 0, like
+// FindAllStringSubmatchIndex. It fails if a match ends with a $ before the final newline and
+// PCRE would find another match from there, which Go's regexp can not do.
+func (p *pregPattern) findAll(subject string, n int) ([][]int, error) {
+	locs := p.re.FindAllStringSubmatchIndex(subject, n)
+	for i, loc := range locs {
+		var moved bool
+		locs[i], moved = p.fix(loc)
+		if moved && (n < 0 || len(locs) < n) {
+			// the latest start of a match tells if there is one from the final newline on
+			re, err := regexp.Compile(`\A(?s:.*)(` + p.expr + `)`)
+			if err != nil {
+				return nil, err
+			}
+			if last := re.FindStringSubmatchIndex(subject); last != nil && last[2] >= locs[i][1] {
+				return nil, errors.New("preg: matching after $ before the final newline is not supported, use the D modifier or \\z")
+			}
+		}
+	}
+	return locs, nil
+}
+
+// pregCompile returns the compiled pattern from the cache or compiles it
+func pregCompile(pattern, subject string) (*pregPattern, error) {
+	pregCacheMu.RLock()
+	p, ok := pregCache[pattern]
+	pregCacheMu.RUnlock()
+
+	if !ok {
+		expr, u, dollars, err := pregTranslate(pattern)
+		if err != nil {
+			return nil, err
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("preg: compilation of %s failed: %v", pattern, err)
+		}
+		p = &pregPattern{re: re, utf8: u, expr: expr, dollars: dollars}
+
+		pregCacheMu.Lock()
+		if len(pregCache) >= pregCacheSize {
+			pregCache = make(map[string]*pregPattern)
+		}
+		pregCache[pattern] = p
+		pregCacheMu.Unlock()
+	}
+
+	if p.utf8 && !utf8.ValidString(subject) {
+		return nil, errors.New("preg: malformed UTF-8 characters, possibly incorrectly encoded")
+	}
+	return p, nil
+}
+
+// pregTranslate converts a PHP pattern with delimiters and modifiers to the syntax of Go's regexp
+func pregTranslate(pattern string) (string, bool, []int, error) {
+	p := strings.TrimLeft(pattern, " \t\n\r\v\f")
+	if p == "" {
+		return "", false, nil, errors.New("preg: empty regular expression")
+	}
+	delim := p[0]
+	if delim >= 'a' && delim <= 'z' || delim >= 'A' && delim <= 'Z' || delim >= '0' && delim <= '9' || delim == '\\' {
+		return "", false, nil, errors.New("preg: delimiter must not be alphanumeric, backslash, or NUL")
+	}
+	end := delim
+	switch delim {
+	case '(':
+		end = ')'
+	case '[':
+		end = ']'
+	case '{':
+		end = '}'
+	case '<':
+		end = '>'
+	}
+
+	// find the ending delimiter, brackets style delimiters may be nested
+	i := 1
+	for depth := 1; i < len(p); i++ {
+		if p[i] == '\\' {
+			i++
+			continue
+		}
+		if p[i] == end {
+			depth--
+		} else if p[i] == delim {
+			depth++
+		}
+		if depth == 0 {
+			break
+		}
+	}
+	if i >= len(p) {
+		return "", false, nil, fmt.Errorf("preg: no ending delimiter '%c' found", end)
+	}
+	expr, modifiers := p[1:i], p[i+1:]
+
+	var flags string
+	var extended, u, dollarEndOnly bool
+	for _, m := range modifiers {
+		switch m {
+		case 'i', 'm', 's', 'U':
+			if !strings.ContainsRune(flags, m) {
+				flags += string(m)
+			}
+		case 'x':
+			extended = true
+		case 'u':
+			u = true
+		case 'D':
+			dollarEndOnly = true
+		case 'S', 'X', '\n', '\r', ' ':
+			// S and X have no effect
+		default:
+			return "", false, nil, fmt.Errorf("preg: unknown or unsupported modifier '%c'", m)
+		}
+	}
+
+	expr, dollars, err := pregConvert(expr, extended, strings.ContainsRune(flags, 'm'), dollarEndOnly)
+	if err != nil {
+		return "", false, nil, fmt.Errorf("preg: %s: %v", pattern, err)
+	}
+	if flags != "" {
+		expr = "(?" + flags + ")" + expr
+	}
+	return expr, u, dollars, nil
+}
+
+// pregConvert rewrites the PCRE only syntax of expr and reports what RE2 can not do
+//
+// Unless dollarEndOnly or multiline, $ and \Z also match before a final newline in PCRE, they are
+// rewritten to (?:\z|()\n\z) and the numbers of the empty groups are returned to find out where
+// the match really ends. multiline follows the inline options like (?m) for each group.
+func pregConvert(expr string, extended, multiline, dollarEndOnly bool) (string, []int, error) {
+	var b strings.Builder
+	var dollars []int
+	var stack []bool
+	groups := 0
+	inClass, quant := false, false
+	dollar := func(rest string) error {
+		if !pregAtBranchEnd(rest, extended) {
+			return errors.New("$ before a final newline followed by more pattern is not supported, use the D modifier or \\z")
+		}
+		groups++
+		dollars = append(dollars, groups)
+		b.WriteString(`(?:\z|()\n\z)`)
+		return nil
+	}
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		wasQuant := quant
+		quant = false
+
+		if c == '\\' && i+1 < len(expr) {
+			n := expr[i+1]
+			i++
+			switch {
+			case n >= '1' && n <= '9' && !inClass, n == 'g', n == 'k':
+				return "", nil, errors.New("backreferences are not supported")
+			case n == 'K' || n == 'G':
+				return "", nil, fmt.Errorf("\\%c is not supported", n)
+			case n == 'Z' && !inClass:
+				if err := dollar(expr[i+1:]); err != nil {
+					return "", nil, err
+				}
+			case n == ' ' || n >= '\t' && n <= '\r':
+				// an escaped whitespace is literal, even with the x modifier
+				b.WriteByte(n)
+			case n == 'h':
+				if inClass {
+					b.WriteString(`\t \x{a0}\x{1680}\x{180e}\x{2000}-\x{200a}\x{202f}\x{205f}\x{3000}`)
+				} else {
+					b.WriteString(`[\t \x{a0}\x{1680}\x{180e}\x{2000}-\x{200a}\x{202f}\x{205f}\x{3000}]`)
+				}
+			case n == 'R' && !inClass:
+				b.WriteString(`(?:\r\n|[\n\v\f\r\x{85}\x{2028}\x{2029}])`)
+			case n == 'e':
+				b.WriteString(`\x1b`)
+			case n == 'Q':
+				// quoted literal text up to \E
+				end := strings.Index(expr[i+1:], `\E`)
+				if end < 0 {
+					end = len(expr) - i - 1
+				}
+				b.WriteString(regexp.QuoteMeta(expr[i+1 : i+1+end]))
+				i += end + 2
+			default:
+				b.WriteByte(c)
+				b.WriteByte(n)
+			}
+			continue
+		}
+
+		if inClass {
+			if c == '[' && i+1 < len(expr) && expr[i+1] == ':' {
+				// POSIX class like [:alpha:]
+				if end := strings.Index(expr[i:], ":]"); end >= 0 {
+					b.WriteString(expr[i : i+end+2])
+					i += end + 1
+					continue
+				}
+			}
+			if c == ']' {
+				inClass = false
+			}
+			b.WriteByte(c)
+			continue
+		}
+
+		switch c {
+		case '$':
+			if !multiline && !dollarEndOnly {
+				if err := dollar(expr[i+1:]); err != nil {
+					return "", nil, err
+				}
+				continue
+			}
+		case ')':
+			if len(stack) > 0 {
+				multiline = stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+			}
+		case '[':
+			inClass = true
+			b.WriteByte(c)
+			// a ] right after [ or [^ is a literal
+			if strings.HasPrefix(expr[i+1:], "^]") {
+				b.WriteString("^]")
+				i += 2
+			} else if strings.HasPrefix(expr[i+1:], "]") {
+				b.WriteByte(']')
+				i++
+			}
+			continue
+		case '(':
+			rest := expr[i+1:]
+			switch {
+			case strings.HasPrefix(rest, "?#"):
+				end := strings.IndexByte(rest, ')')
+				if end < 0 {
+					return "", nil, errors.New("missing ) after comment")
+				}
+				i += end + 1
+				continue
+			case strings.HasPrefix(rest, "?="), strings.HasPrefix(rest, "?!"),
+				strings.HasPrefix(rest, "?<="), strings.HasPrefix(rest, "?<!"):
+				return "", nil, errors.New("lookahead and lookbehind assertions are not supported")
+			case strings.HasPrefix(rest, "?>"):
+				return "", nil, errors.New("atomic groups are not supported")
+			case strings.HasPrefix(rest, "?R"), strings.HasPrefix(rest, "?&"), strings.HasPrefix(rest, "?P>"),
+				len(rest) > 1 && rest[0] == '?' && (rest[1] >= '0' && rest[1] <= '9' || rest[1] == '+' || rest[1] == '-' && len(rest) > 2 && rest[2] >= '0' && rest[2] <= '9'):
+				return "", nil, errors.New("recursion is not supported")
+			case strings.HasPrefix(rest, "?("):
+				return "", nil, errors.New("conditional subpatterns are not supported")
+			case strings.HasPrefix(rest, "?'"):
+				end := strings.IndexByte(rest[2:], '\'')
+				if end < 0 {
+					return "", nil, errors.New("syntax error in subpattern name (missing terminator)")
+				}
+				b.WriteString("(?P<" + rest[2:2+end] + ">")
+				i += end + 3
+				groups++
+				stack = append(stack, multiline)
+				continue
+			case strings.HasPrefix(rest, "?P="):
+				return "", nil, errors.New("backreferences are not supported")
+			}
+			if flags, scoped, n := pregInlineFlags(rest); n > 0 {
+				m := pregFlagM(flags, multiline)
+				if !scoped {
+					// (?m) applies to the rest of the enclosing group
+					multiline = m
+					b.WriteString(expr[i : i+n+1])
+					i += n
+					continue
+				}
+				stack = append(stack, multiline)
+				multiline = m
+				break
+			}
+			if !strings.HasPrefix(rest, "?") || strings.HasPrefix(rest, "?P<") || strings.HasPrefix(rest, "?<") {
+				groups++
+			}
+			stack = append(stack, multiline)
+		case '*', '+', '?':
+			if wasQuant && c == '+' {
+				return "", nil, errors.New("possessive quantifiers are not supported")
+			}
+			quant = c != '?' || !wasQuant
+		case '}':
+			quant = true
+		case ' ', '\t', '\n', '\r', '\v', '\f':
+			if extended {
+				quant = wasQuant
+				continue
+			}
+		case '#':
+			if extended {
+				if end := strings.IndexByte(expr[i:], '\n'); end >= 0 {
+					i += end
+				} else {
+					i = len(expr)
+				}
+				quant = wasQuant
+				continue
+			}
+		}
+		b.WriteByte(c)
+	}
+	return b.String(), dollars, nil
+}
+
+// pregInlineFlags parses an option setting like "?i-m)" or "?s:" following "(", n is its length or 0
+func pregInlineFlags(rest string) (flags string, scoped bool, n int) {
+	if len(rest) < 2 || rest[0] != '?' {
+		return "", false, 0
+	}
+	for j := 1; j < len(rest); j++ {
+		switch c := rest[j]; {
+		case c == ')' && j > 1, c == ':':
+			return rest[1:j], c == ':', j + 1
+		case strings.IndexByte("-imsUx", c) < 0:
+			return "", false, 0
+		}
+	}
+	return "", false, 0
+}
+
+// pregFlagM returns whether the m option is set after applying flags like "i-m"
+func pregFlagM(flags string, multiline bool) bool {
+	on := true
+	for _, c := range flags {
+		switch c {
+		case '-':
+			on = false
+		case 'm':
+			multiline = on
+		}
+	}
+	return multiline
+}
+
+// pregAtBranchEnd reports if nothing but closing parentheses follow until the end of the pattern or
+// of the alternative, whitespace and comments are skipped with the x modifier
+func pregAtBranchEnd(rest string, extended bool) bool {
+	for i := 0; i < len(rest); i++ {
+		switch c := rest[i]; {
+		case c == '|':
+			return true
+		case c == ')':
+		case extended && (c == ' ' || c >= '\t' && c <= '\r'):
+		case extended && c == '#':
+			end := strings.IndexByte(rest[i:], '\n')
+			if end < 0 {
+				return true
+			}
+			i += end
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,101 @@
+package php
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPregMatchDollar(t *testing.T) {
+	tests := []struct {
+		pattern, subject string
+		want             []string
+	}{
+		{`/a$/`, "a", []string{"a"}},
+		{`/a$/`, "a\n", []string{"a"}},
+		{`/a$/`, "a\n\n", nil},
+		{`/a$/D`, "a\n", nil},
+		{`/a\z/`, "a\n", nil},
+		{`/a\Z/`, "a\n", []string{"a"}},
+		{`/a\Z/D`, "a\n", []string{"a"}},
+		{`/(a+)$/`, "baa\n", []string{"aa", "aa"}},
+		{`/(a\n?)$/`, "a\n", []string{"a\n", "a\n"}},
+		{`/(a$)|(b)/`, "a\n", []string{"a", "a"}},
+		{`/x$|(b)/`, "b\n", []string{"b", "b"}},
+		{`/^a$/m`, "a\nb", []string{"a"}},
+		{`/(?m)^a$/`, "a\nb", []string{"a"}},
+		{`/(?m:a$)\nb/`, "a\nb", []string{"a\nb"}},
+		{`/[$]/`, "$", []string{"$"}},
+		{`/a\$/`, "a$", []string{"a$"}},
+		{`/(?P<n>a)$/`, "a\n", []string{"a", "a"}},
+		{`/a $ # end/x`, "a\n", []string{"a"}},
+	}
+	for _, test := range tests {
+		got, err := PregMatch(test.pattern, test.subject)
+		if err != nil {
+			t.Errorf("PregMatch(%q, %q) error: %v", test.pattern, test.subject, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("PregMatch(%q, %q) = %q, want %q", test.pattern, test.subject, got, test.want)
+		}
+	}
+
+	for _, pattern := range []string{`/a$b/`, `/a$\n/`, `/(a$)b/`, `/a\Zb/`} {
+		if _, err := PregMatch(pattern, "a\n"); err == nil {
+			t.Errorf("PregMatch(%q) expected an error", pattern)
+		}
+	}
+	if _, err := PregMatch(`/a$b/D`, "a\n"); err != nil {
+		t.Errorf("PregMatch(%q) error: %v", `/a$b/D`, err)
+	}
+}
+
+func TestPregDollarAll(t *testing.T) {
+	all, err := PregMatchAll(`/\w+$/`, "foo bar\n", PregPatternOrder)
+	if err != nil || !reflect.DeepEqual(all, [][]string{{"bar"}}) {
+		t.Errorf("PregMatchAll = %q, %v", all, err)
+	}
+	got, err := PregReplace(`/o$/`, "0", "foo\n", -1)
+	if err != nil || got != "fo0\n" {
+		t.Errorf("PregReplace = %q, %v", got, err)
+	}
+	split, err := PregSplit(`/,$/`, "a,b,\n", -1, 0)
+	if err != nil || !reflect.DeepEqual(split, []string{"a,b", "\n"}) {
+		t.Errorf("PregSplit = %q, %v", split, err)
+	}
+	if _, err := PregMatchAll(`/$/`, "a\n", PregPatternOrder); err == nil {
+		t.Error("PregMatchAll expected an error for a match after $ before the final newline")
+	}
+}
+
+func TestPregExtendedEscapedSpace(t *testing.T) {
+	tests := []struct {
+		pattern, subject string
+		want             []string
+	}{
+		{`/a\ b/x`, "a b", []string{"a b"}},
+		{`/a \ b/x`, "ab a b", []string{"a b"}},
+		{`/a[\ ]b/x`, "a b", []string{"a b"}},
+		{"/a\\\tb/x", "a\tb", []string{"a\tb"}},
+		{`/a\ b/`, "a b", []string{"a b"}},
+	}
+	for _, test := range tests {
+		got, err := PregMatch(test.pattern, test.subject)
+		if err != nil {
+			t.Errorf("PregMatch(%q, %q) error: %v", test.pattern, test.subject, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("PregMatch(%q, %q) = %q, want %q", test.pattern, test.subject, got, test.want)
+		}
+	}
+}
+
+func TestPregReplaceError(t *testing.T) {
+	if got, err := PregReplace(3.5, "x", "subject", -1); err == nil || got != "" {
+		t.Errorf("PregReplace with an unsupported pattern type = %q, %v", got, err)
+	}
+	if got, err := PregReplace(`/(/`, "x", "subject", -1); err == nil || got != "" {
+		t.Errorf("PregReplace with an invalid pattern = %q, %v", got, err)
+	}
+}
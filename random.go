@@ -0,0 +1,176 @@
+package php
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+)
+
+var (
+	uniqidMutex sync.Mutex
+	uniqidPrev  int64
+	uuidMutex   sync.Mutex
+	uuidPrev    int64
+)
+
+// UuidInfo is the result of UuidParse
+type UuidInfo struct {
+	Bytes   [16]byte
+	Version int
+	Time    int64 // the Unix time in milliseconds of a version 7 UUID, 0 otherwise
+}
+
+// RandomBytes get cryptographically secure random bytes
+//
+// see http://php.net/manual/en/function.random-bytes.php
+func RandomBytes(length int) (string, error) {
+	if length < 1 {
+		return "", errors.New("length must be greater than 0")
+	}
+	b := make([]byte, length)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// RandomInt get a cryptographically secure, uniformly selected integer between min and max inclusive
+//
+// see http://php.net/manual/en/function.random-int.php
+func RandomInt(min, max int) (int, error) {
+	if min > max {
+		return 0, errors.New("min must be less than or equal to max")
+	}
+	// the width of the range wraps correctly even if it overflows int
+	umax := uint64(max) - uint64(min)
+	var b [8]byte
+	for {
+		if _, err := rand.Read(b[:]); err != nil {
+			return 0, err
+		}
+		n := binary.LittleEndian.Uint64(b[:])
+		if umax == math.MaxUint64 {
+			return min + int(n), nil
+		}
+		// reject the values of the last incomplete period to avoid the modulo bias
+		if n <= math.MaxUint64-(math.MaxUint64%(umax+1)+1)%(umax+1) {
+			return min + int(n%(umax+1)), nil
+		}
+	}
+}
+
+// Uniqid generate a time-based identifier of 13 hexadecimal characters prefixed with prefix
+//
+// The identifier is made of the seconds and the microseconds of the current time, it waits for the
+// microsecond to change so two calls never return the same value. With moreEntropy a random number
+// like "4.12345678" is appended, which makes it 23 characters.
+// .eg Uniqid("") returns "64f5b0e1a3c2d", Uniqid("a", true) returns "a64f5b0e1a3c2e7.81209438"
+//
+// see http://php.net/manual/en/function.uniqid.php
+func Uniqid(prefix string, moreEntropy ...bool) string {
+	uniqidMutex.Lock()
+	usec := nowTime().UnixNano() / 1000
+	for usec == uniqidPrev {
+		usec = nowTime().UnixNano() / 1000
+	}
+	uniqidPrev = usec
+	uniqidMutex.Unlock()
+
+	id := fmt.Sprintf("%s%08x%05x", prefix, usec/1000000, usec%1000000)
+	if len(moreEntropy) > 0 && moreEntropy[0] {
+		var b [4]byte
+		rand.Read(b[:])
+		id += fmt.Sprintf("%.8f", float64(binary.LittleEndian.Uint32(b[:]))/(1<<32)*10)
+	}
+	return id
+}
+
+// UuidV4 generate a random UUID
+// .eg UuidV4() returns "3f2b8c1e-9d4a-4f6b-8e2c-7a1d5b9c0e4f"
+func UuidV4() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return formatUuid(b), nil
+}
+
+// UuidV7 generate a time-ordered UUID
+//
+// The first 48 bits are the Unix time in milliseconds and the next 12 bits the fraction of the
+// millisecond, the rest is random. The UUIDs generated by the process are strictly increasing.
+// .eg UuidV7() returns "018f3c2a-7b4e-7c1d-9a2b-3c4d5e6f7a8b"
+func UuidV7() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[8:]); err != nil {
+		return "", err
+	}
+	uuidMutex.Lock()
+	nsec := nowTime().UnixNano()
+	ts := nsec/1000000<<12 | nsec%1000000*4096/1000000
+	if ts <= uuidPrev {
+		ts = uuidPrev + 1
+	}
+	uuidPrev = ts
+	uuidMutex.Unlock()
+
+	binary.BigEndian.PutUint64(b[:8], uint64(ts)<<4)
+	b[6] = 0x70 | byte(ts>>8)&0x0f
+	b[7] = byte(ts)
+	b[8] = b[8]&0x3f | 0x80
+	return formatUuid(b), nil
+}
+
+// UuidParse parse a UUID in its canonical form, the braces, the "urn:uuid:" prefix and the hyphens
+// are optional and the case is ignored
+// .eg UuidParse("018f3c2a-7b4e-7c1d-9a2b-3c4d5e6f7a8b") returns UuidInfo{Version: 7, Time: 1714701368142, ...}
+func UuidParse(uuid string) (UuidInfo, error) {
+	var info UuidInfo
+	s := uuid
+	if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+		s = s[1 : len(s)-1]
+	} else if len(s) > 9 && strings.EqualFold(s[:9], "urn:uuid:") {
+		s = s[9:]
+	}
+	if len(s) == 36 {
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return info, fmt.Errorf("invalid UUID: %s", uuid)
+		}
+		s = s[:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	}
+	if len(s) != 32 {
+		return info, fmt.Errorf("invalid UUID: %s", uuid)
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return info, fmt.Errorf("invalid UUID: %s", uuid)
+	}
+	copy(info.Bytes[:], b)
+	info.Version = int(info.Bytes[6] >> 4)
+	if info.Version == 7 {
+		info.Time = int64(binary.BigEndian.Uint64(info.Bytes[:8]) >> 16)
+	}
+	return info, nil
+}
+
+// formatUuid writes b in the canonical form xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx
+func formatUuid(b [16]byte) string {
+	buf := make([]byte, 36)
+	hex.Encode(buf, b[:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:], b[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:], b[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:], b[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], b[10:])
+	return string(buf)
+}
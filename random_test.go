@@ -0,0 +1,24 @@
+package php
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestUniqid(t *testing.T) {
+	re := regexp.MustCompile(`^p[0-9a-f]{13}[0-9]\.[0-9]{8}$`)
+	prev := ""
+	for i := 0; i < 100; i++ {
+		id := Uniqid("p", true)
+		if !re.MatchString(id) {
+			t.Fatalf("Uniqid(\"p\", true) = %q", id)
+		}
+		if id[:14] == prev {
+			t.Fatalf("Uniqid returned %q twice", prev)
+		}
+		prev = id[:14]
+	}
+	if id := Uniqid(""); len(id) != 13 {
+		t.Errorf("Uniqid(\"\") = %q", id)
+	}
+}
@@ -0,0 +1,364 @@
+package php
+
+import "strings"
+
+// Levenshtein calculate Levenshtein distance between two strings
+//
+// The distance is counted in bytes like PHP, use MbLevenshtein for multi-byte strings. The optional
+// costs are the insertion, replacement and deletion costs, 1 by default.
+// .eg Levenshtein("kitten", "sitting") returns 3, Levenshtein("kitten", "sitting", 1, 10, 1) returns 5
+//
+// see http://php.net/manual/en/function.levenshtein.php
+func Levenshtein(s1, s2 string, costs ...int) int {
+	return levenshtein(len(s1), len(s2), func(i, j int) bool { return s1[i] == s2[j] }, costs)
+}
+
+// MbLevenshtein is the multi-byte safe version of Levenshtein, the distance is counted in characters
+// .eg MbLevenshtein("张三丰", "张三") returns 1
+func MbLevenshtein(s1, s2 string, costs ...int) int {
+	r1, r2 := []rune(s1), []rune(s2)
+	return levenshtein(len(r1), len(r2), func(i, j int) bool { return r1[i] == r2[j] }, costs)
+}
+
+// levenshtein is the port of PHP's reference_levdist, eq reports if the i-th element of
+// the first string equals the j-th element of the second one
+func levenshtein(l1, l2 int, eq func(i, j int) bool, costs []int) int {
+	costIns, costRep, costDel := 1, 1, 1
+	if len(costs) > 0 {
+		costIns = costs[0]
+	}
+	if len(costs) > 1 {
+		costRep = costs[1]
+	}
+	if len(costs) > 2 {
+		costDel = costs[2]
+	}
+
+	if l1 == 0 {
+		return l2 * costIns
+	}
+	if l2 == 0 {
+		return l1 * costDel
+	}
+
+	p1, p2 := make([]int, l2+1), make([]int, l2+1)
+	for i := range p1 {
+		p1[i] = i * costIns
+	}
+	for i := 0; i < l1; i++ {
+		p2[0] = p1[0] + costDel
+		for j := 0; j < l2; j++ {
+			c0 := p1[j]
+			if !eq(i, j) {
+				c0 += costRep
+			}
+			if c1 := p1[j+1] + costDel; c1 < c0 {
+				c0 = c1
+			}
+			if c2 := p2[j] + costIns; c2 < c0 {
+				c0 = c2
+			}
+			p2[j+1] = c0
+		}
+		p1, p2 = p2, p1
+	}
+	return p1[l2]
+}
+
+// SimilarText calculate the similarity between two strings
+//
+// It returns the number of matching bytes in both strings, and the similarity in percent is stored
+// to the optional percent, the same as PHP.
+// .eg SimilarText("World", "Word", &percent) returns 4 and percent is 88.888...
+//
+// see http://php.net/manual/en/function.similar-text.php
+func SimilarText(s1, s2 string, percent ...*float64) int {
+	if len(s1)+len(s2) == 0 {
+		if len(percent) > 0 && percent[0] != nil {
+			*percent[0] = 0
+		}
+		return 0
+	}
+	sim := similarChar(s1, s2)
+	if len(percent) > 0 && percent[0] != nil {
+		*percent[0] = float64(sim) * 200 / float64(len(s1)+len(s2))
+	}
+	return sim
+}
+
+// similarChar is the port of PHP's php_similar_char
+func similarChar(s1, s2 string) int {
+	pos1, pos2, max, count := similarStr(s1, s2)
+	sum := max
+	if sum == 0 {
+		return 0
+	}
+	if pos1 > 0 && pos2 > 0 && count > 1 {
+		sum += similarChar(s1[:pos1], s2[:pos2])
+	}
+	if pos1+max < len(s1) && pos2+max < len(s2) {
+		sum += similarChar(s1[pos1+max:], s2[pos2+max:])
+	}
+	return sum
+}
+
+// similarStr finds the first longest common substring of s1 and s2 like PHP's php_similar_str
+func similarStr(s1, s2 string) (pos1, pos2, max, count int) {
+	for p := 0; p < len(s1); p++ {
+		for q := 0; q < len(s2); q++ {
+			l := 0
+			for p+l < len(s1) && q+l < len(s2) && s1[p+l] == s2[q+l] {
+				l++
+			}
+			if l > max {
+				max = l
+				count++
+				pos1, pos2 = p, q
+			}
+		}
+	}
+	return
+}
+
+// Soundex calculate the soundex key of a string
+//
+// The key is a letter followed by three digits like "R163", non-letters are ignored and
+// an empty string is returned if there is no letter.
+//
+// see http://php.net/manual/en/function.soundex.php
+func Soundex(str string) string {
+	// the codes of A to Z, 0 for the vowels and H, W, Y
+	const table = "01230120022455012623010202"
+
+	res := make([]byte, 0, 4)
+	var last byte
+	for i := 0; i < len(str) && len(res) < 4; i++ {
+		c := str[i]
+		if 'a' <= c && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		if c < 'A' || c > 'Z' {
+			continue
+		}
+		code := table[c-'A']
+		if len(res) == 0 {
+			res = append(res, c)
+			last = code
+		} else if code != last {
+			if code != '0' {
+				res = append(res, code)
+			}
+			last = code
+		}
+	}
+	if len(res) == 0 {
+		return ""
+	}
+	for len(res) < 4 {
+		res = append(res, '0')
+	}
+	return string(res)
+}
+
+// Metaphone calculate the metaphone key of a string
+//
+// The key is made of the letters "0BFHJKLMNPRSTWXY" where "0" is "th" and "X" is "sh", the same as PHP.
+// The optional maxPhonemes limits the length of the key, 0 means no limit.
+// .eg Metaphone("Thumb") returns "0M", Metaphone("Knight") returns "NFT"
+//
+// see http://php.net/manual/en/function.metaphone.php
+func Metaphone(str string, maxPhonemes ...int) string {
+	max := 0
+	if len(maxPhonemes) > 0 && maxPhonemes[0] > 0 {
+		max = maxPhonemes[0]
+	}
+	if i := strings.IndexByte(str, 0); i >= 0 {
+		str = str[:i]
+	}
+
+	// letter returns the upper case letter at i, 0 if it's out of str
+	letter := func(i int) byte {
+		if i < 0 || i >= len(str) {
+			return 0
+		}
+		c := str[i]
+		if 'a' <= c && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		return c
+	}
+	isAlpha := func(c byte) bool { return 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' }
+	// the flags of A to Z: 1 vowels AEIOU, 2 FJMNR, 4 CGPST which form diphthongs when followed by H,
+	// 8 EIY which make C and G soft, 16 BDH which prevent GH from becoming F
+	const codes = "\x01\x10\x04\x10\x09\x02\x04\x10\x09\x02\x00\x02\x02\x02\x01\x04\x00\x02\x04\x04\x01\x00\x00\x00\x08\x00"
+	is := func(c byte, flag byte) bool { return 'A' <= c && c <= 'Z' && codes[c-'A']&flag != 0 }
+
+	var res []byte
+	w := 0
+
+	// skip leading non-alpha
+	for ; !isAlpha(letter(w)); w++ {
+		if letter(w) == 0 {
+			return ""
+		}
+	}
+
+	// handle the prefixes
+	switch letter(w) {
+	case 'A':
+		// AE becomes E, the other vowels at the beginning are preserved
+		if letter(w+1) == 'E' {
+			res = append(res, 'E')
+			w += 2
+		} else {
+			res = append(res, 'A')
+			w++
+		}
+	case 'G', 'K', 'P':
+		// [GKP]N becomes N
+		if letter(w+1) == 'N' {
+			res = append(res, 'N')
+			w += 2
+		}
+	case 'W':
+		// WR becomes R, WH and W followed by a vowel become W
+		if letter(w+1) == 'R' {
+			res = append(res, 'R')
+			w += 2
+		} else if letter(w+1) == 'H' || is(letter(w+1), 1) {
+			res = append(res, 'W')
+			w += 2
+		}
+	case 'X':
+		res = append(res, 'S')
+		w++
+	case 'E', 'I', 'O', 'U':
+		res = append(res, letter(w))
+		w++
+	}
+
+	for ; letter(w) != 0 && (max == 0 || len(res) < max); w++ {
+		cur, prev, next := letter(w), letter(w-1), letter(w+1)
+		afterNext := byte(0)
+		if next != 0 {
+			afterNext = letter(w + 2)
+		}
+		skip := 0
+
+		// ignore non-alphas and drop duplicates except CC
+		if !isAlpha(cur) || (cur == prev && cur != 'C') {
+			continue
+		}
+
+		switch cur {
+		case 'B':
+			// B unless in MB
+			if prev != 'M' {
+				res = append(res, 'B')
+			}
+		case 'C':
+			if is(next, 8) {
+				if next == 'I' && afterNext == 'A' {
+					res = append(res, 'X') // CIA
+				} else if prev != 'S' {
+					res = append(res, 'S') // SC[IEY] is dropped
+				}
+			} else if next == 'H' {
+				res = append(res, 'X')
+				skip++
+			} else {
+				res = append(res, 'K')
+			}
+		case 'D':
+			// J if in DGE, DGI or DGY, else T
+			if next == 'G' && is(afterNext, 8) {
+				res = append(res, 'J')
+				skip++
+			} else {
+				res = append(res, 'T')
+			}
+		case 'G':
+			if next == 'H' {
+				// F if in GH and not B--GH, D--GH, -H--GH, -H---GH
+				if !(is(letter(w-3), 16) || letter(w-4) == 'H') {
+					res = append(res, 'F')
+					skip++
+				}
+			} else if next == 'N' {
+				// dropped if in GN or GNED
+				if !(!isAlpha(afterNext) || (afterNext == 'E' && lookahead(str, w, 3) == 'D')) {
+					res = append(res, 'K')
+				}
+			} else if is(next, 8) && prev != 'G' {
+				res = append(res, 'J')
+			} else {
+				res = append(res, 'K')
+			}
+		case 'H':
+			// H if before a vowel and not after C, G, P, S, T
+			if is(next, 1) && !is(prev, 4) {
+				res = append(res, 'H')
+			}
+		case 'K':
+			if prev != 'C' {
+				res = append(res, 'K')
+			}
+		case 'P':
+			if next == 'H' {
+				res = append(res, 'F')
+			} else {
+				res = append(res, 'P')
+			}
+		case 'Q':
+			res = append(res, 'K')
+		case 'S':
+			if next == 'I' && (afterNext == 'O' || afterNext == 'A') {
+				res = append(res, 'X')
+			} else if next == 'H' {
+				res = append(res, 'X')
+				skip++
+			} else {
+				res = append(res, 'S')
+			}
+		case 'T':
+			if next == 'I' && (afterNext == 'O' || afterNext == 'A') {
+				res = append(res, 'X')
+			} else if next == 'H' {
+				res = append(res, '0')
+				skip++
+			} else if !(next == 'C' && afterNext == 'H') {
+				res = append(res, 'T')
+			}
+		case 'V':
+			res = append(res, 'F')
+		case 'W':
+			if is(next, 1) {
+				res = append(res, 'W')
+			}
+		case 'X':
+			res = append(res, 'K', 'S')
+		case 'Y':
+			if is(next, 1) {
+				res = append(res, 'Y')
+			}
+		case 'Z':
+			res = append(res, 'S')
+		case 'F', 'J', 'L', 'M', 'N', 'R':
+			res = append(res, cur)
+		}
+		w += skip
+	}
+	return string(res)
+}
+
+// lookahead returns the upper case letter n bytes after w in str, or 0 if str ends before it
+func lookahead(str string, w, n int) byte {
+	if w+n >= len(str) {
+		return 0
+	}
+	c := str[w+n]
+	if 'a' <= c && c <= 'z' {
+		c -= 'a' - 'A'
+	}
+	return c
+}
@@ -0,0 +1,35 @@
+package php
+
+import "testing"
+
+func TestMetaphone(t *testing.T) {
+	tests := []struct {
+		str         string
+		maxPhonemes int
+		want        string
+	}{
+		{"Thompson", 0, "0MPSN"},
+		{"Thompson", 2, "0M"},
+		{"Thomas", 0, "0MS"},
+		{"Thames", 0, "0MS"},
+		{"Simpson", 0, "SMPSN"},
+		{"Mathematics", 0, "M0MTKS"},
+		{"Thumb", 0, "0M"},
+		{"Thin", 0, "0N"},
+		{"Smith", 0, "SM0"},
+		{"Knight", 0, "NFT"},
+		{"Philip", 0, "FLP"},
+		{"Champion", 0, "XMPN"},
+		{"Asterix", 5, "ASTRKS"},
+		{"Aeon", 0, "EN"},
+		{"Wright", 0, "RFT"},
+		{"Xavier", 0, "SFR"},
+		{"", 0, ""},
+		{"123", 0, ""},
+	}
+	for _, test := range tests {
+		if got := Metaphone(test.str, test.maxPhonemes); got != test.want {
+			t.Errorf("Metaphone(%q, %d) = %q, want %q", test.str, test.maxPhonemes, got, test.want)
+		}
+	}
+}
@@ -2,14 +2,19 @@ package php
 
 import (
 	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"hash/crc32"
-	"io"
-	"os"
-	"regexp"
+	"math"
 	"strconv"
 	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/width"
 )
 
 // Substr returns the portion of string specified by the start and length parameters.
@@ -24,9 +29,7 @@ import (
 //
 // 2) If length is 0, the substring starting from start until the end of the string will be returned.
 func Substr(str string, start, length int) string {
-
-	rs := []rune(str)
-	rl := len(rs)
+	rl := utf8.RuneCountInString(str)
 
 	if rl == 0 {
 		return ""
@@ -57,57 +60,268 @@ func Substr(str string, start, length int) string {
 		end = rl
 	}
 
-	return string(rs[start:end])
+	if rl == len(str) {
+		return validUTF8(str[start:end])
+	}
+	from := runeIndex(str, start)
+	return validUTF8(str[from : from+runeIndex(str[from:], end-start)])
+}
+
+// validUTF8 returns str itself if it's valid UTF-8, otherwise every invalid byte is replaced
+// with U+FFFD, like converting it to []rune and back
+func validUTF8(str string) string {
+	if utf8.ValidString(str) {
+		return str
+	}
+	return string([]rune(str))
 }
 
 // Strlen get string length
 //
 // A multi-byte character is counted as 1
 func Strlen(str string) int {
-	return len([]rune(str))
+	return utf8.RuneCountInString(str)
 }
 
-// Strpos find position of first occurrence of string in a string
+// MbStrwidth return width of string
+//
+// Characters of East Asian Width property Wide or Fullwidth, like CJK ideographs, kana, hangul,
+// fullwidth forms and most emoji, are counted as 2, the others are counted as 1.
+//
+// see http://php.net/manual/en/function.mb-strwidth.php
+func MbStrwidth(str string) int {
+	w := 0
+	for _, r := range str {
+		w += runeWidth(r)
+	}
+	return w
+}
+
+// MbStrimwidth get truncated string with specified width
 //
-// It's multi-byte safe. return -1 if can not find the substring
-func Strpos(haystack, needle string) int {
+// start is counted in characters and can be negative to count from the end of str,
+// a negative width counts from the end of str too. When str is truncated the optional
+// trimMarker is appended, and the result including it is no wider than width.
+//
+// see http://php.net/manual/en/function.mb-strimwidth.php
+func MbStrimwidth(str string, start, width int, trimMarker ...string) string {
+	marker := ""
+	if len(trimMarker) > 0 {
+		marker = trimMarker[0]
+	}
+
+	rs := []rune(str)
+	rl := len(rs)
+	if start < 0 {
+		start += rl
+	}
+	if start < 0 || start > rl {
+		return ""
+	}
+	rs = rs[start:]
 
-	pos := strings.Index(haystack, needle)
+	total := 0
+	for _, r := range rs {
+		total += runeWidth(r)
+	}
+	if width < 0 {
+		width += total
+	}
+	if total <= width {
+		return string(rs)
+	}
 
-	if pos < 0 {
-		return pos
+	width -= MbStrwidth(marker)
+	if width <= 0 {
+		return marker
+	}
+	w, end := 0, 0
+	for end < len(rs) && w+runeWidth(rs[end]) <= width {
+		w += runeWidth(rs[end])
+		end++
 	}
+	return string(rs[:end]) + marker
+}
 
-	rs := []rune(haystack[0:pos])
+// runeWidth returns the number of columns r takes in monospaced fonts
+func runeWidth(r rune) int {
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	}
+	return 1
+}
 
-	return len(rs)
+// Strpos find position of first occurrence of string in a string
+//
+// It's multi-byte safe, the position is counted in characters. return -1 if can not find the substring.
+// The optional offset is where the search starts, a negative offset counts from the end of haystack,
+// and -1 is returned if the offset is out of haystack, like PHP's mb_strpos.
+//
+// see http://php.net/manual/en/function.mb-strpos.php
+func Strpos(haystack, needle string, offset ...int) int {
+	return strpos(haystack, needle, offset, false, false)
 }
 
 // Strrpos find the position of the last occurrence of a substring in a string
-func Strrpos(haystack, needle string) int {
+//
+// A positive offset skips characters from the beginning of haystack, a negative offset stops searching
+// at that many characters from the end, the same as PHP's mb_strrpos.
+//
+// see http://php.net/manual/en/function.mb-strrpos.php
+func Strrpos(haystack, needle string, offset ...int) int {
+	return strpos(haystack, needle, offset, true, false)
+}
+
+// Stripos find position of the first occurrence of a case-insensitive substring in a string
+//
+// The strings are compared with Unicode case folding, the same as Ireplace.
+//
+// see http://php.net/manual/en/function.mb-stripos.php
+func Stripos(haystack, needle string, offset ...int) int {
+	return strpos(haystack, needle, offset, false, true)
+}
 
-	pos := strings.LastIndex(haystack, needle)
+// Strripos find the position of the last occurrence of a case-insensitive substring in a string
+//
+// see http://php.net/manual/en/function.mb-strripos.php
+func Strripos(haystack, needle string, offset ...int) int {
+	return strpos(haystack, needle, offset, true, true)
+}
+
+// strpos is the helper function of the Strpos family, the positions are counted in runes
+func strpos(haystack, needle string, offsets []int, reverse, ignoreCase bool) int {
+	offset := 0
+	if len(offsets) > 0 {
+		offset = offsets[0]
+	}
+	length := utf8.RuneCountInString(haystack)
+	if offset > length || offset < -length {
+		return -1
+	}
+	var folded []rune
+	if ignoreCase {
+		folded = foldString(needle)
+	}
+
+	var start, end int
+	if !reverse {
+		if offset < 0 {
+			offset += length
+		}
+		if needle == "" {
+			return offset
+		}
+		start = runeIndex(haystack, offset)
+		end = len(haystack)
+	} else {
+		// the match must start between offset and maxStart
+		nlen := utf8.RuneCountInString(needle)
+		maxStart := length - nlen
+		if offset < 0 {
+			if length+offset < maxStart {
+				maxStart = length + offset
+			}
+			offset = 0
+		}
+		if maxStart < offset {
+			return -1
+		}
+		if needle == "" {
+			return maxStart
+		}
+		start = runeIndex(haystack, offset)
+		end = start + runeIndex(haystack[start:], maxStart-offset+nlen)
+	}
 
+	s := haystack[start:end]
+	var pos int
+	switch {
+	case !reverse && ignoreCase:
+		pos, _ = indexFold(s, folded)
+	case !reverse:
+		pos = strings.Index(s, needle)
+	case ignoreCase:
+		pos, _ = lastIndexFold(s, folded)
+	default:
+		pos = strings.LastIndex(s, needle)
+	}
 	if pos < 0 {
-		return pos
+		return -1
 	}
+	return offset + utf8.RuneCountInString(s[:pos])
+}
 
-	rs := []rune(haystack[0:pos])
+// runeIndex returns the byte index of the n-th rune of str, or len(str) if there are not so many
+func runeIndex(str string, n int) int {
+	i := 0
+	for ; n > 0 && i < len(str); n-- {
+		_, size := utf8.DecodeRuneInString(str[i:])
+		i += size
+	}
+	return i
+}
 
-	return len(rs)
+// SubstrCount count the number of substring occurrences
+//
+// The occurrences do not overlap. The optional params are offset and length in characters like Substr,
+// negative values count from the end of haystack. 0 is returned if needle is empty or the offset
+// and length are out of haystack.
+//
+// see http://php.net/manual/en/function.mb-substr-count.php
+func SubstrCount(haystack, needle string, params ...int) int {
+	if needle == "" {
+		return 0
+	}
+	if len(params) > 0 {
+		length := utf8.RuneCountInString(haystack)
+		offset := params[0]
+		if offset < 0 {
+			offset += length
+		}
+		if offset < 0 || offset > length {
+			return 0
+		}
+		haystack = haystack[runeIndex(haystack, offset):]
+		if len(params) > 1 {
+			l := params[1]
+			if l < 0 {
+				l += length - offset
+			}
+			if l < 0 || l > length-offset {
+				return 0
+			}
+			haystack = haystack[:runeIndex(haystack, l)]
+		}
+	}
+	return strings.Count(haystack, needle)
 }
 
-// Stripos find position of the first occurrence of a case-insensitive substring in a string
-func Stripos(haystack, needle string) int {
-	return Strpos(strings.ToLower(haystack), strings.ToLower(needle))
+// StrContains determine if a string contains a given substring, an empty needle is always contained
+//
+// see http://php.net/manual/en/function.str-contains.php
+func StrContains(haystack, needle string) bool {
+	return strings.Contains(haystack, needle)
 }
 
-// Strripos find the position of the last occurrence of a case-insensitive substring in a string
-func Strripos(haystack, needle string) int {
-	return Strrpos(strings.ToLower(haystack), strings.ToLower(needle))
+// StrStartsWith checks if a string starts with a given substring
+//
+// see http://php.net/manual/en/function.str-starts-with.php
+func StrStartsWith(haystack, needle string) bool {
+	return strings.HasPrefix(haystack, needle)
+}
+
+// StrEndsWith checks if a string ends with a given substring
+//
+// see http://php.net/manual/en/function.str-ends-with.php
+func StrEndsWith(haystack, needle string) bool {
+	return strings.HasSuffix(haystack, needle)
 }
 
 // buildReplaceSlice is a helper function for Replace and Ireplace
+//
+// The replace slice returned has the same length as the search slice, a single replace
+// value is used for every search value and missing replace values are empty strings.
 func buildReplaceSlice(search, replace interface{}) ([]string, []string, error) {
 
 	var aSearch, aReplace []string
@@ -125,6 +339,7 @@ func buildReplaceSlice(search, replace interface{}) ([]string, []string, error)
 		return aSearch, aReplace, errors.New("unsupported type of search")
 	}
 
+	single := true
 	switch r := replace.(type) {
 	case int:
 		aReplace = append(aReplace, strconv.Itoa(r))
@@ -134,59 +349,486 @@ func buildReplaceSlice(search, replace interface{}) ([]string, []string, error)
 		aReplace = append(aReplace, r)
 	case []string:
 		aReplace = r
+		single = false
 	default:
 		return aSearch, aReplace, errors.New("unsupported type of replace")
 	}
 
-	return aSearch, aReplace, nil
+	res := make([]string, len(aSearch))
+	for i := range res {
+		if single {
+			res[i] = aReplace[0]
+		} else if i < len(aReplace) {
+			res[i] = aReplace[i]
+		}
+	}
+
+	return aSearch, res, nil
 }
 
-// Replace all occurrences of the search string with the replacement string
-//
-// This function is an implement of PHP's str_replace
+// replacerCacheSize is the number of replacers kept for the search and replace arguments
+const replacerCacheSize = 4096
+
+var (
+	replacerCache   = make(map[replacerKey]*replacer)
+	replacerCacheMu sync.RWMutex
+)
+
+// replacerKey identifies the replacer of the search and replace pairs, pairs is the strings
+// prefixed with their lengths
+type replacerKey struct {
+	pairs      string
+	ignoreCase bool
+}
+
+// replacer holds the search and replace pairs of Replace and Ireplace, it's built once
+// and applied to every subject
 //
-// see http://php.net/manual/en/function.str-replace.php
-func Replace(search, replace interface{}, subject string) string {
+// The pairs are split into stages of consecutive pairs which can not find the replacement of
+// an earlier pair of the stage, so each stage replaces all of its pairs in a single pass.
+type replacer struct {
+	stages     []*replaceStage
+	ignoreCase bool
+}
 
-	var aSearch, aReplace []string
+// replaceStage is a run of pairs found with one trie of their search strings, the trie values
+// are the indexes of replace. A case-sensitive stage of a single pair uses search instead.
+type replaceStage struct {
+	search  string
+	trie    *trie
+	replace []string
+}
+
+// replaceMatch is an occurrence of the search string of pair index at start:end
+type replaceMatch struct {
+	start, end, index int
+}
 
+// getReplacer returns the replacer from the cache or builds it
+func getReplacer(search, replace interface{}, ignoreCase bool) (*replacer, error) {
 	aSearch, aReplace, err := buildReplaceSlice(search, replace)
 	if err != nil {
-		return subject
+		return nil, err
+	}
+	if len(aSearch) == 1 && !ignoreCase {
+		// nothing to build but a strings.Replace
+		return newReplacer(aSearch, aReplace, ignoreCase), nil
+	}
+
+	var b strings.Builder
+	for i, s := range aSearch {
+		for _, str := range [2]string{s, aReplace[i]} {
+			b.WriteString(strconv.Itoa(len(str)))
+			b.WriteByte(':')
+			b.WriteString(str)
+		}
+	}
+	key := replacerKey{pairs: b.String(), ignoreCase: ignoreCase}
+
+	replacerCacheMu.RLock()
+	r, ok := replacerCache[key]
+	replacerCacheMu.RUnlock()
+	if !ok {
+		r = newReplacer(aSearch, aReplace, ignoreCase)
+
+		replacerCacheMu.Lock()
+		if len(replacerCache) >= replacerCacheSize {
+			replacerCache = make(map[replacerKey]*replacer)
+		}
+		replacerCache[key] = r
+		replacerCacheMu.Unlock()
+	}
+	return r, nil
+}
+
+// newReplacer builds the replacer, empty search strings are skipped like PHP does
+func newReplacer(aSearch, aReplace []string, ignoreCase bool) *replacer {
+	r := &replacer{ignoreCase: ignoreCase}
+	var keys, folded []string // of the current stage, folded for Ireplace
+	flush := func() {
+		if len(keys) == 0 {
+			return
+		}
+		st := r.stages[len(r.stages)-1]
+		if len(keys) == 1 && !ignoreCase {
+			st.search = keys[0]
+		} else {
+			st.trie = newTrie()
+			for i, key := range keys {
+				// a search string repeated in the stage is all replaced by the first pair
+				if v, n := st.trie.longest(key); v < 0 || n != len(key) {
+					st.trie.insert(key, i)
+				}
+			}
+		}
+		keys, folded = keys[:0], folded[:0]
 	}
 
-	r := ""
 	for index, s := range aSearch {
-		if index < len(aReplace) {
-			r = aReplace[index]
+		if s == "" {
+			continue
+		}
+		key, rep := s, aReplace[index]
+		if ignoreCase {
+			key, rep = string(foldString(key)), string(foldString(rep))
+		}
+		chained := len(keys) == 0
+		for _, f := range folded {
+			if chained = replaceChains(key, f); chained {
+				break
+			}
+		}
+		if chained {
+			flush()
+			r.stages = append(r.stages, &replaceStage{})
+		}
+		st := r.stages[len(r.stages)-1]
+		st.replace = append(st.replace, aReplace[index])
+		keys, folded = append(keys, key), append(folded, rep)
+	}
+	flush()
+	return r
+}
+
+// replaceChains reports if key may be found in a subject where an earlier pair put replace,
+// or across the joint of the text around an empty replace
+func replaceChains(key, replace string) bool {
+	if replace == "" {
+		return len(key) > 1
+	}
+	// key starts at the offset d of replace, and they overlap
+	for d := 1 - len(key); d < len(replace); d++ {
+		lo, hi := max(d, 0), min(d+len(key), len(replace))
+		if replace[lo:hi] == key[lo-d:hi-d] {
+			return true
+		}
+	}
+	return false
+}
+
+// apply replaces the pairs stage by stage and returns the number of replacements performed
+func (r *replacer) apply(subject string) (string, int) {
+	count := 0
+	for _, st := range r.stages {
+		var n int
+		subject, n = st.apply(subject, r.ignoreCase)
+		count += n
+	}
+	return subject, count
+}
+
+// apply replaces the pairs of the stage as if they were replaced one after another
+func (st *replaceStage) apply(s string, ignoreCase bool) (string, int) {
+	if st.trie == nil {
+		n := strings.Count(s, st.search)
+		if n > 0 {
+			s = strings.Replace(s, st.search, st.replace[0], -1)
+		}
+		return s, n
+	}
+
+	// every occurrence of every search string in the order of their starts, Ireplace only
+	// matches at the start of a character and walks the trie with the folded characters
+	var found []replaceMatch
+	var buf [utf8.UTFMax]byte
+	for i := 0; i < len(s); {
+		size := 1
+		if ignoreCase {
+			_, size = utf8.DecodeRuneInString(s[i:])
+		}
+		n := int32(0)
+		for j := i; j < len(s) && n >= 0; {
+			if ignoreCase {
+				r, sz := utf8.DecodeRuneInString(s[j:])
+				w := utf8.EncodeRune(buf[:], foldRune(r))
+				for k := 0; k < w && n >= 0; k++ {
+					n = st.trie.step(n, buf[k])
+				}
+				j += sz
+			} else {
+				n = st.trie.step(n, s[j])
+				j++
+			}
+			if n >= 0 && st.trie.nodes[n].value >= 0 {
+				found = append(found, replaceMatch{i, j, int(st.trie.nodes[n].value)})
+			}
 		}
-		subject = strings.Replace(subject, s, r, -1)
+		i += size
+	}
+	if len(found) == 0 {
+		return s, 0
+	}
+
+	// group the occurrences by pair, keeping their order
+	first := make([]int, len(st.replace)+1)
+	for _, m := range found {
+		first[m.index+1]++
+	}
+	for i := 1; i < len(first); i++ {
+		first[i] += first[i-1]
+	}
+	byPair := make([]int, len(found))
+	next := append([]int(nil), first...)
+	for f, m := range found {
+		byPair[next[m.index]] = f
+		next[m.index]++
+	}
+
+	// like str_replace each pair in turn replaces its leftmost occurrences which overlap
+	// neither each other nor the ones replaced by the earlier pairs
+	taken := make([]bool, len(s))
+	replaced := make([]bool, len(found))
+	count := 0
+	for index := range st.replace {
+		pos := 0
+	occurrences:
+		for _, f := range byPair[first[index]:first[index+1]] {
+			m := found[f]
+			if m.start < pos {
+				continue
+			}
+			for _, t := range taken[m.start:m.end] {
+				if t {
+					continue occurrences
+				}
+			}
+			for i := m.start; i < m.end; i++ {
+				taken[i] = true
+			}
+			replaced[f] = true
+			pos = m.end
+			count++
+		}
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	last := 0
+	for f, m := range found {
+		if replaced[f] {
+			b.WriteString(s[last:m.start])
+			b.WriteString(st.replace[m.index])
+			last = m.end
+		}
+	}
+	b.WriteString(s[last:])
+	return b.String(), count
+}
+
+// Replace all occurrences of the search string with the replacement string
+//
+// This function is an implement of PHP's str_replace, search and replace can be a string or []string.
+// The pairs are applied one after another, so a later pair may replace the result of an earlier one,
+// use Strtr to replace them in a single pass. The number of replacements performed is added to the
+// optional count.
+//
+// see http://php.net/manual/en/function.str-replace.php
+func Replace(search, replace interface{}, subject string, count ...*int) string {
+	res := ReplaceSlice(search, replace, []string{subject}, count...)
+	if res == nil {
+		return subject
 	}
+	return res[0]
+}
 
-	return subject
+// ReplaceSlice is Replace with a slice of subjects, it returns a new slice of the replaced subjects
+func ReplaceSlice(search, replace interface{}, subjects []string, count ...*int) []string {
+	return replaceSlice(search, replace, subjects, false, count...)
 }
 
 // Ireplace is case-insensitive version of Replace()
-func Ireplace(search, replace interface{}, subject string) string {
+//
+// The search strings are matched literally using Unicode case folding, so "ǅ" matches "ǆ"
+// and "K" matches the Kelvin sign.
+//
+// see http://php.net/manual/en/function.str-ireplace.php
+func Ireplace(search, replace interface{}, subject string, count ...*int) string {
+	res := IreplaceSlice(search, replace, []string{subject}, count...)
+	if res == nil {
+		return subject
+	}
+	return res[0]
+}
 
-	var aSearch, aReplace []string
+// IreplaceSlice is Ireplace with a slice of subjects, it returns a new slice of the replaced subjects
+func IreplaceSlice(search, replace interface{}, subjects []string, count ...*int) []string {
+	return replaceSlice(search, replace, subjects, true, count...)
+}
 
-	aSearch, aReplace, err := buildReplaceSlice(search, replace)
+// Strtr translate characters
+//
+// Every byte of from in str is translated into the byte at the same position of to,
+// the extra bytes of the longer one are ignored. It works byte by byte like PHP's strtr,
+// use StrtrPairs for multi-byte characters.
+//
+// see http://php.net/manual/en/function.strtr.php
+func Strtr(str, from, to string) string {
+	n := len(from)
+	if len(to) < n {
+		n = len(to)
+	}
+	if n == 0 {
+		return str
+	}
+
+	var table [256]byte
+	for i := range table {
+		table[i] = byte(i)
+	}
+	for i := 0; i < n; i++ {
+		table[from[i]] = to[i]
+	}
+	b := []byte(str)
+	for i, c := range b {
+		b[i] = table[c]
+	}
+	return string(b)
+}
+
+// StrtrPairs replace substrings in the form of PHP's strtr(string, array)
+//
+// str is scanned once, at every position the longest key of replacePairs is replaced,
+// and the replaced text is never searched again. Empty keys are ignored. Use NewStrtr to
+// replace many strings with the same pairs.
+func StrtrPairs(str string, replacePairs map[string]string) string {
+	return NewStrtr(replacePairs).Replace(str)
+}
+
+// StrtrReplacer replaces substrings like StrtrPairs with the pairs compiled once,
+// it's safe for concurrent use by multiple goroutines
+type StrtrReplacer struct {
+	trie   *trie
+	values []string
+}
+
+// NewStrtr returns a StrtrReplacer of the pairs, empty keys are ignored
+// .eg NewStrtr(map[string]string{"Hi": "Hello", "Hello": "Hi"}).Replace("Hi all, Hello") returns "Hello all, Hi"
+func NewStrtr(replacePairs map[string]string) *StrtrReplacer {
+	r := &StrtrReplacer{trie: newTrie(), values: make([]string, 0, len(replacePairs))}
+	for k, v := range replacePairs {
+		if k == "" {
+			continue
+		}
+		r.trie.insert(k, len(r.values))
+		r.values = append(r.values, v)
+	}
+	return r
+}
+
+// Replace returns a copy of str with the pairs replaced, see StrtrPairs
+func (r *StrtrReplacer) Replace(str string) string {
+	if len(r.values) == 0 {
+		return str
+	}
+
+	var b strings.Builder
+	last := 0
+	for i := 0; i < len(str); {
+		v, n := r.trie.longest(str[i:])
+		if v < 0 {
+			i++
+			continue
+		}
+		b.WriteString(str[last:i])
+		b.WriteString(r.values[v])
+		i += n
+		last = i
+	}
+	if last == 0 {
+		return str
+	}
+	b.WriteString(str[last:])
+	return b.String()
+}
+
+// replaceSlice is a helper function for ReplaceSlice and IreplaceSlice
+func replaceSlice(search, replace interface{}, subjects []string, ignoreCase bool, count ...*int) []string {
+	r, err := getReplacer(search, replace, ignoreCase)
 	if err != nil {
-		return subject
+		return nil
 	}
 
-	r := ""
-	var reg *regexp.Regexp
-	for index, s := range aSearch {
-		if index < len(aReplace) {
-			r = aReplace[index]
+	res := make([]string, len(subjects))
+	total := 0
+	for i, subject := range subjects {
+		var n int
+		res[i], n = r.apply(subject)
+		total += n
+	}
+	if len(count) > 0 && count[0] != nil {
+		*count[0] += total
+	}
+	return res
+}
+
+// foldRune maps r to the smallest rune of its Unicode simple case folding orbit,
+// two runes are equal ignoring case if their foldRune are the same
+func foldRune(r rune) rune {
+	if r < utf8.RuneSelf {
+		if r >= 'a' && r <= 'z' {
+			r -= 'a' - 'A'
 		}
-		reg = regexp.MustCompile("(?i:" + s + ")")
-		subject = reg.ReplaceAllString(subject, r)
+		return r
+	}
+	min := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < min {
+			min = f
+		}
+	}
+	return min
+}
+
+// foldString returns the folded runes of str
+func foldString(str string) []rune {
+	rs := []rune(str)
+	for i, r := range rs {
+		rs[i] = foldRune(r)
 	}
+	return rs
+}
 
-	return subject
+// indexFold returns the byte range of the first case-insensitive occurrence of the folded
+// needle in s, start is -1 if there is none. needle must not be empty.
+func indexFold(s string, needle []rune) (start, end int) {
+	for i := 0; i < len(s); {
+		if j := matchFold(s[i:], needle); j >= 0 {
+			return i, i + j
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+	}
+	return -1, -1
+}
+
+// lastIndexFold returns the byte range of the last case-insensitive occurrence of the folded
+// needle in s, start is -1 if there is none. needle must not be empty.
+func lastIndexFold(s string, needle []rune) (start, end int) {
+	for i := len(s); i > 0; {
+		_, size := utf8.DecodeLastRuneInString(s[:i])
+		i -= size
+		if j := matchFold(s[i:], needle); j >= 0 {
+			return i, i + j
+		}
+	}
+	return -1, -1
+}
+
+// matchFold returns the length in bytes of the case-insensitive match of the folded needle
+// at the beginning of s, or -1 if it does not match
+func matchFold(s string, needle []rune) int {
+	j := 0
+	for _, n := range needle {
+		if j >= len(s) {
+			return -1
+		}
+		r, size := utf8.DecodeRuneInString(s[j:])
+		if foldRune(r) != n {
+			return -1
+		}
+		j += size
+	}
+	return j
 }
 
 // Addslashes quote string with slashes
@@ -201,18 +843,574 @@ func Stripslashes(str string) string {
 	return Replace([]string{"\\\\", "\\'", "\\\""}, []string{"\\", "'", "\""}, str)
 }
 
-// Chr returns a one-character string containing the character specified by ascii
+// Addcslashes quote string with slashes in a C style
+//
+// charlist is a list of characters to be escaped, ranges like "A..Z" are supported.
+// Non-printable characters are converted to the C escape (\n, \t, ...) or octal representation.
+//
+// see http://php.net/manual/en/function.addcslashes.php
+func Addcslashes(str, charlist string) string {
+	mask := charMask(charlist)
+
+	var b strings.Builder
+	b.Grow(len(str))
+	for i := 0; i < len(str); i++ {
+		c := str[i]
+		if !mask[c] {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('\\')
+		if c >= 32 && c <= 126 {
+			b.WriteByte(c)
+			continue
+		}
+		switch c {
+		case '\n':
+			b.WriteByte('n')
+		case '\t':
+			b.WriteByte('t')
+		case '\r':
+			b.WriteByte('r')
+		case '\a':
+			b.WriteByte('a')
+		case '\v':
+			b.WriteByte('v')
+		case '\b':
+			b.WriteByte('b')
+		case '\f':
+			b.WriteByte('f')
+		default:
+			fmt.Fprintf(&b, "%03o", c)
+		}
+	}
+	return b.String()
+}
+
+// Flags of Htmlspecialchars, Htmlentities, HtmlspecialcharsDecode and HtmlEntityDecode
+const (
+	// EntNoQuotes will leave both double and single quotes unconverted
+	EntNoQuotes int = 0
+	// EntCompat will convert double-quotes and leave single-quotes alone
+	EntCompat int = 2
+	// EntQuotes will convert both double and single quotes
+	EntQuotes int = 3
+	// EntIgnore silently discards invalid code unit sequences instead of returning an empty string
+	EntIgnore int = 4
+	// EntSubstitute replaces invalid code unit sequences with U+FFFD
+	EntSubstitute int = 8
+	// EntDisallowed replaces invalid code points for the given document type with U+FFFD
+	EntDisallowed int = 128
+
+	// EntHTML401 handles code as HTML 4.01
+	EntHTML401 int = 0
+	// EntXML1 handles code as XML 1
+	EntXML1 int = 16
+	// EntXHTML handles code as XHTML
+	EntXHTML int = 32
+	// EntHTML5 handles code as HTML 5
+	EntHTML5 int = 48
+
+	entQuoteSingle int = 1
+	entQuoteDouble int = 2
+)
+
+// Htmlspecialchars convert special characters to HTML entities
+//
+// This function is an implement of PHP's htmlspecialchars, flags is a combination of the Ent* constants,
+// PHP's default is EntQuotes | EntSubstitute | EntHTML401
+//
+// see http://php.net/manual/en/function.htmlspecialchars.php
+func Htmlspecialchars(str string, flags int, doubleEncode bool) string {
+	return htmlEncode(str, flags, doubleEncode, false)
+}
+
+// Htmlentities convert all applicable characters to HTML entities
+//
+// see http://php.net/manual/en/function.htmlentities.php
+func Htmlentities(str string, flags int, doubleEncode bool) string {
+	return htmlEncode(str, flags, doubleEncode, true)
+}
+
+// HtmlspecialcharsDecode convert special HTML entities back to characters
+//
+// see http://php.net/manual/en/function.htmlspecialchars-decode.php
+func HtmlspecialcharsDecode(str string, flags int) string {
+	return htmlDecode(str, flags, false)
+}
+
+// HtmlEntityDecode convert HTML entities to their corresponding characters
+//
+// see http://php.net/manual/en/function.html-entity-decode.php
+func HtmlEntityDecode(str string, flags int) string {
+	return htmlDecode(str, flags, true)
+}
+
+// htmlEncode is a helper function for Htmlspecialchars and Htmlentities
+func htmlEncode(str string, flags int, doubleEncode, all bool) string {
+	doctype := flags & (EntXML1 | EntXHTML)
+	table := htmlEncodeTables[doctype]
+
+	var b strings.Builder
+	b.Grow(len(str))
+	for i := 0; i < len(str); {
+		r, size, ok := decodeRune(str[i:])
+		if !ok {
+			if flags&EntSubstitute != 0 {
+				b.WriteRune(utf8.RuneError)
+			} else if flags&EntIgnore == 0 {
+				return ""
+			}
+			i += size
+			continue
+		}
+		c := str[i : i+size]
+		i += size
+
+		switch {
+		case r == '&':
+			if !doubleEncode {
+				if n := htmlEntityLen(str[i:], doctype, flags); n > 0 {
+					b.WriteString(str[i-1 : i+n])
+					i += n
+					continue
+				}
+			}
+			b.WriteString("&amp;")
+			continue
+		case r == '"' && flags&entQuoteDouble != 0:
+			b.WriteString("&quot;")
+			continue
+		case r == '\'' && flags&entQuoteSingle != 0:
+			if doctype == EntHTML401 {
+				b.WriteString("&#039;")
+			} else {
+				b.WriteString("&apos;")
+			}
+			continue
+		case r == '<':
+			b.WriteString("&lt;")
+			continue
+		case r == '>':
+			b.WriteString("&gt;")
+			continue
+		case r == '"' || r == '\'':
+			b.WriteString(c)
+			continue
+		}
+
+		if all {
+			// a few HTML5 entities are made of two code points
+			if doctype == EntHTML5 && i < len(str) {
+				_, n := utf8.DecodeRuneInString(str[i:])
+				if name, ok := table[c+str[i:i+n]]; ok {
+					b.WriteString("&" + name + ";")
+					i += n
+					continue
+				}
+			}
+			if name, ok := table[c]; ok {
+				b.WriteString("&" + name + ";")
+				continue
+			}
+		}
+
+		if flags&EntDisallowed != 0 && !htmlCodePointAllowed(r, doctype) {
+			b.WriteRune(utf8.RuneError)
+			continue
+		}
+		b.WriteString(c)
+	}
+	return b.String()
+}
+
+// htmlDecode is a helper function for HtmlspecialcharsDecode and HtmlEntityDecode
+func htmlDecode(str string, flags int, all bool) string {
+	doctype := flags & (EntXML1 | EntXHTML)
+	table := htmlDecodeTable(doctype)
+	if !all {
+		table = htmlEntitiesXML1
+	}
+
+	var b strings.Builder
+	b.Grow(len(str))
+	for {
+		pos := strings.IndexByte(str, '&')
+		if pos < 0 {
+			b.WriteString(str)
+			break
+		}
+		b.WriteString(str[:pos])
+		str = str[pos+1:]
+
+		name, code, n := parseHTMLEntity(str)
+		var val string
+		switch {
+		case n == 0:
+		case name == "":
+			if !all && !strings.ContainsRune("&\"'<>", code) {
+				break
+			}
+			if !htmlCodePointAllowed(code, doctype) || (doctype == EntHTML5 && code == 0x0D) {
+				break
+			}
+			val = string(code)
+		case doctype == EntHTML401 && name == "apos":
+			// &apos; is not an HTML 4.01 entity
+		default:
+			if v, ok := table[name]; ok {
+				val = v
+			} else if doctype == EntXHTML && name == "apos" {
+				val = "'"
+			}
+		}
+		if val == "" || (val == "'" && flags&entQuoteSingle == 0) || (val == "\"" && flags&entQuoteDouble == 0) {
+			b.WriteByte('&')
+			continue
+		}
+		b.WriteString(val)
+		str = str[n:]
+	}
+	return b.String()
+}
+
+// parseHTMLEntity parses the character reference following an ampersand,
+// name is empty for numeric references, n is 0 if there is no valid one
+func parseHTMLEntity(str string) (name string, code rune, n int) {
+	if strings.HasPrefix(str, "#") {
+		base, start := 10, 1
+		if len(str) > 1 && (str[1] == 'x' || str[1] == 'X') {
+			base, start = 16, 2
+		}
+		end := start
+		for end < len(str) && strings.IndexByte("0123456789abcdefABCDEF"[:base+(base-10)], str[end]) >= 0 {
+			end++
+		}
+		if end == start || end >= len(str) || str[end] != ';' {
+			return "", 0, 0
+		}
+		c, err := strconv.ParseInt(str[start:end], base, 64)
+		if err != nil || c > utf8.MaxRune {
+			return "", 0, 0
+		}
+		return "", rune(c), end + 1
+	}
+
+	end := 0
+	for end < len(str) && (str[end] >= 'a' && str[end] <= 'z' || str[end] >= 'A' && str[end] <= 'Z' || str[end] >= '0' && str[end] <= '9') {
+		end++
+	}
+	if end == 0 || end >= len(str) || str[end] != ';' {
+		return "", 0, 0
+	}
+	return str[:end], 0, end + 1
+}
+
+// htmlEntityLen returns the length of the valid character reference following an
+// ampersand, it's used to keep existing entities when double encoding is turned off
+func htmlEntityLen(str string, doctype, flags int) int {
+	name, code, n := parseHTMLEntity(str)
+	if n == 0 {
+		return 0
+	}
+	if name == "" {
+		if flags&EntDisallowed != 0 && !htmlNumericEntityAllowed(code, doctype) {
+			return 0
+		}
+		return n
+	}
+	if _, ok := htmlDecodeTable(doctype)[name]; ok || (doctype == EntXHTML && name == "apos") {
+		return n
+	}
+	return 0
+}
+
+// decodeRune unpacks the first UTF-8 encoding in str like utf8.DecodeRuneInString,
+// but on invalid sequences it skips the maximal ill-formed subpart as PHP does
+func decodeRune(str string) (rune, int, bool) {
+	r, size := utf8.DecodeRuneInString(str)
+	if r != utf8.RuneError || size > 1 {
+		return r, size, true
+	}
+	if len(str) == 0 {
+		return r, 0, false
+	}
+
+	lead := func(c byte) bool { return c < 0x80 || (c >= 0xC2 && c <= 0xF4) }
+	c := str[0]
+	var need int
+	switch {
+	case c >= 0xC2 && c <= 0xDF:
+		need = 2
+	case c >= 0xE0 && c <= 0xEF:
+		need = 3
+	case c >= 0xF0 && c <= 0xF4:
+		need = 4
+	default:
+		return r, 1, false
+	}
+	n := 1
+	for n < need && n < len(str) && !lead(str[n]) {
+		n++
+	}
+	return r, n, false
+}
+
+// StripTags strip HTML and PHP tags from a string
+//
+// allowedTags specifies tags which should not be stripped, each of them can be
+// either a tag name like "a" or a list of tags like "<a><b>"
+//
+// see http://php.net/manual/en/function.strip-tags.php
+func StripTags(str string, allowedTags ...string) string {
+	var allow string
+	for _, t := range allowedTags {
+		if strings.HasPrefix(t, "<") {
+			allow += t
+		} else {
+			allow += "<" + t + ">"
+		}
+	}
+	allow = strings.ToLower(allow)
+
+	at := func(i int) byte {
+		if i < 0 || i >= len(str) {
+			return 0
+		}
+		return str[i]
+	}
+
+	var (
+		b, tag    strings.Builder
+		state     int // 0 text, 1 HTML tag, 2 PHP code, 3 <! declaration, 4 comment
+		depth, br int
+		inQ, lc   byte
+		isXML     bool
+	)
+	for i := 0; i < len(str); i++ {
+		c := str[i]
+		switch state {
+		case 0:
+			switch c {
+			case 0:
+			case '<':
+				if inQ != 0 {
+					break
+				}
+				if isSpace(at(i + 1)) {
+					b.WriteByte(c)
+					break
+				}
+				lc = '<'
+				state = 1
+				tag.Reset()
+				tag.WriteByte(c)
+			case '>':
+				if depth > 0 {
+					depth--
+				} else if inQ == 0 {
+					b.WriteByte(c)
+				}
+			default:
+				b.WriteByte(c)
+			}
+		case 1:
+			switch c {
+			case 0:
+			case '<':
+				if inQ != 0 {
+					break
+				}
+				if isSpace(at(i + 1)) {
+					tag.WriteByte(c)
+					break
+				}
+				depth++
+			case '>':
+				if depth > 0 {
+					depth--
+					break
+				}
+				if inQ != 0 {
+					break
+				}
+				lc = '>'
+				if isXML && at(i-1) == '-' {
+					break
+				}
+				inQ, state, isXML = 0, 0, false
+				tag.WriteByte(c)
+				if allow != "" && stripTagsFind(tag.String(), allow) {
+					b.WriteString(tag.String())
+				}
+				tag.Reset()
+			case '"', '\'':
+				if i != 0 && (inQ == 0 || c == inQ) {
+					if inQ != 0 {
+						inQ = 0
+					} else {
+						inQ = c
+					}
+				}
+				tag.WriteByte(c)
+			case '!':
+				// JavaScript & other HTML scripting languages
+				if at(i-1) == '<' {
+					state = 3
+					lc = c
+				} else {
+					tag.WriteByte(c)
+				}
+			case '?':
+				if at(i-1) == '<' {
+					br = 0
+					state = 2
+				} else {
+					tag.WriteByte(c)
+				}
+			default:
+				tag.WriteByte(c)
+			}
+		case 2:
+			switch c {
+			case '(':
+				if lc != '"' && lc != '\'' {
+					lc = '('
+					br++
+				}
+			case ')':
+				if lc != '"' && lc != '\'' {
+					lc = ')'
+					br--
+				}
+			case '>':
+				if depth > 0 {
+					depth--
+					break
+				}
+				if inQ != 0 {
+					break
+				}
+				if br == 0 && lc != '"' && at(i-1) == '?' {
+					inQ, state = 0, 0
+					tag.Reset()
+				}
+			case '"', '\'':
+				if i >= 1 && str[i-1] != '\\' {
+					if lc == c {
+						lc = 0
+					} else if lc != '\\' {
+						lc = c
+					}
+					if inQ == 0 || c == inQ {
+						if inQ != 0 {
+							inQ = 0
+						} else {
+							inQ = c
+						}
+					}
+				}
+			case 'l', 'L':
+				// '<?xml' is not PHP code, switch back to HTML
+				if i > 4 && (at(i-1) == 'm' || at(i-1) == 'M') && (at(i-2) == 'x' || at(i-2) == 'X') && at(i-3) == '?' && at(i-4) == '<' {
+					state = 1
+					isXML = true
+				}
+			}
+		case 3:
+			switch c {
+			case '>':
+				if depth > 0 {
+					depth--
+					break
+				}
+				if inQ != 0 {
+					break
+				}
+				inQ, state = 0, 0
+				tag.Reset()
+			case '"', '\'':
+				if i != 0 && at(i-1) != '\\' && (inQ == 0 || c == inQ) {
+					if inQ != 0 {
+						inQ = 0
+					} else {
+						inQ = c
+					}
+				}
+			case '-':
+				if at(i-1) == '-' && at(i-2) == '!' {
+					state = 4
+				}
+			case 'E', 'e':
+				// !DOCTYPE exception
+				if i > 6 && strings.EqualFold(str[i-6:i], "doctyp") {
+					state = 1
+				}
+			}
+		case 4:
+			if c == '>' && inQ == 0 && at(i-1) == '-' && at(i-2) == '-' {
+				inQ, state = 0, 0
+				tag.Reset()
+			}
+		}
+	}
+	return b.String()
+}
+
+// stripTagsFind normalizes tag like <a href="x"> or </a> into <a> and checks if it's in the allowed set
+func stripTagsFind(tag, set string) bool {
+	norm := []byte{}
+	started := false
+loop:
+	for t := 0; t < len(tag); t++ {
+		c := tag[t]
+		if c >= 'A' && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		switch {
+		case c == '<':
+			norm = append(norm, c)
+		case c == '>':
+			break loop
+		case !isSpace(c):
+			started = true
+			if c != '/' || (tag[t-1] != '<' && tag[t+1] != '>') {
+				norm = append(norm, c)
+			}
+		case started:
+			break loop
+		}
+	}
+	norm = append(norm, '>')
+	return strings.Contains(set, string(norm))
+}
+
+// isSpace checks if c is a white-space character in C locale
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\v' || c == '\f' || c == '\r'
+}
+
+// Chr generate a single-byte string from a number
+//
+// Like PHP, ascii is taken modulo 256, so Chr(200) is the byte "\xc8" and Chr(-1) is "\xff".
+//
+// see http://php.net/manual/en/function.chr.php
 func Chr(ascii int) string {
-	for ascii < 0 {
+	ascii %= 256
+	if ascii < 0 {
 		ascii += 256
 	}
-	ascii %= 256
-	return string(ascii)
+	return string([]byte{byte(ascii)})
 }
 
-// Ord return ASCII value of character
+// Ord convert the first byte of a string to a value between 0 and 255, 0 for an empty string
+//
+// see http://php.net/manual/en/function.ord.php
 func Ord(character string) rune {
-	return []rune(character)[0]
+	if character == "" {
+		return 0
+	}
+	return rune(character[0])
 }
 
 // Explode returns an slice of strings, each of which is a substring of str
@@ -227,14 +1425,142 @@ func Implode(glue string, pieces []string) string {
 	return strings.Join(pieces, glue)
 }
 
+// defaultTrimChars is the characters stripped by Trim, Ltrim and Rtrim by default
+const defaultTrimChars = " \t\n\r\x00\x0B"
+
+// Trim strip whitespace (or other characters) from the beginning and end of a string
+//
+// The optional charlist is the characters to be stripped, ranges like "a..z" are supported.
+// Without it " \t\n\r\0\x0B" will be stripped.
+//
+// see http://php.net/manual/en/function.trim.php
+func Trim(str string, charlist ...string) string {
+	return trim(str, 3, charlist...)
+}
+
+// Ltrim strip whitespace (or other characters) from the beginning of a string
+func Ltrim(str string, charlist ...string) string {
+	return trim(str, 1, charlist...)
+}
+
+// Rtrim strip whitespace (or other characters) from the end of a string
+func Rtrim(str string, charlist ...string) string {
+	return trim(str, 2, charlist...)
+}
+
+// trim is a helper function for Trim, Ltrim and Rtrim, mode 1 trims left, 2 trims right and 3 both
+func trim(str string, mode int, charlist ...string) string {
+	chars := defaultTrimChars
+	if len(charlist) > 0 {
+		chars = charlist[0]
+	}
+	mask := charMask(chars)
+
+	start, end := 0, len(str)
+	if mode&1 != 0 {
+		for start < end && mask[str[start]] {
+			start++
+		}
+	}
+	if mode&2 != 0 {
+		for end > start && mask[str[end-1]] {
+			end--
+		}
+	}
+	return str[start:end]
+}
+
+// charMask builds the byte set described by a PHP character list like "a..z0..9_"
+func charMask(charlist string) [256]bool {
+	var mask [256]bool
+	for i := 0; i < len(charlist); i++ {
+		c := charlist[i]
+		if i+3 < len(charlist) && charlist[i+1] == '.' && charlist[i+2] == '.' && charlist[i+3] >= c {
+			for j := int(c); j <= int(charlist[i+3]); j++ {
+				mask[j] = true
+			}
+			i += 3
+		} else if i+1 < len(charlist) && c == '.' && charlist[i+1] == '.' {
+			// invalid range, PHP ignores the dot
+			continue
+		} else {
+			mask[c] = true
+		}
+	}
+	return mask
+}
+
+// defaultMbTrimChars is the Unicode white-space characters stripped by MbTrim by default
+const defaultMbTrimChars = " \f\n\r\t\v\x00\u00a0\u1680\u2000..\u200a\u2028\u2029\u202f\u205f\u3000\u0085\u180e"
+
+// MbTrim strip whitespace (or other characters) from the beginning and end of a string
+//
+// It's multi-byte safe, the optional charlist is a list of characters and supports ranges like "а..я".
+// Without it the Unicode white-space characters will be stripped.
+//
+// see https://www.php.net/manual/en/function.mb-trim.php
+func MbTrim(str string, charlist ...string) string {
+	chars := defaultMbTrimChars
+	if len(charlist) > 0 {
+		chars = charlist[0]
+	}
+	in := runeMask(chars)
+	return strings.TrimFunc(str, in)
+}
+
+// runeMask is the multi-byte version of charMask, it returns a function that
+// reports whether a rune is in the list
+func runeMask(charlist string) func(rune) bool {
+	rs := []rune(charlist)
+	set := make(map[rune]bool, len(rs))
+	var ranges [][2]rune
+	for i := 0; i < len(rs); i++ {
+		if i+3 < len(rs) && rs[i+1] == '.' && rs[i+2] == '.' && rs[i+3] >= rs[i] {
+			ranges = append(ranges, [2]rune{rs[i], rs[i+3]})
+			i += 3
+		} else if i+1 < len(rs) && rs[i] == '.' && rs[i+1] == '.' {
+			continue
+		} else {
+			set[rs[i]] = true
+		}
+	}
+	return func(r rune) bool {
+		if set[r] {
+			return true
+		}
+		for _, rg := range ranges {
+			if r >= rg[0] && r <= rg[1] {
+				return true
+			}
+		}
+		return false
+	}
+}
+
 // Lcfirst make a string's first character lowercase
 func Lcfirst(str string) string {
-	return strings.ToLower(Substr(str, 0, 1)) + Substr(str, 1, 0)
+	return mapFirst(str, unicode.ToLower)
 }
 
 // Ucfirst make a string's first character uppercase
 func Ucfirst(str string) string {
-	return strings.ToUpper(Substr(str, 0, 1)) + Substr(str, 1, 0)
+	return mapFirst(str, unicode.ToUpper)
+}
+
+// mapFirst maps the first character of str with mapping, str is returned as it is if nothing changes
+func mapFirst(str string, mapping func(rune) rune) string {
+	if str == "" {
+		return ""
+	}
+	r, size := utf8.DecodeRuneInString(str)
+	rest := validUTF8(str[size:])
+	c := mapping(r)
+	if c == r && (r != utf8.RuneError || size > 1) && len(rest) == len(str)-size {
+		return str
+	}
+	b := make([]byte, 0, utf8.UTFMax+len(rest))
+	b = utf8.AppendRune(b, c)
+	return string(append(b, rest...))
 }
 
 // Md5 calculate the md5 hash of a string
@@ -244,19 +1570,7 @@ func Md5(str string) string {
 
 // Md5File calculates the md5 hash of a given file
 func Md5File(filename string) (string, error) {
-	f, err := os.Open(filename)
-	if err != nil {
-		return "", err
-	}
-	defer f.Close()
-
-	h := md5.New()
-
-	if _, err := io.Copy(h, f); err != nil {
-		return "", err
-	}
-
-	return fmt.Sprintf("%x", h.Sum(nil)), nil
+	return HashFile("md5", filename)
 }
 
 // Strstr find the first occurrence of a string
@@ -270,7 +1584,7 @@ func Strstr(haystack, needle string) string {
 
 // Stristr is case-insensitive Strstr()
 func Stristr(haystack, needle string) string {
-	pos := Stripos(strings.ToLower(haystack), strings.ToLower(needle))
+	pos := Stripos(haystack, needle)
 	if pos < 0 {
 		return ""
 	}
@@ -281,3 +1595,390 @@ func Stristr(haystack, needle string) string {
 func Crc32(str string) uint32 {
 	return crc32.ChecksumIEEE([]byte(str))
 }
+
+// Base64Encode encodes data with MIME base64
+//
+// see http://php.net/manual/en/function.base64-encode.php
+func Base64Encode(str string) string {
+	return base64.StdEncoding.EncodeToString([]byte(str))
+}
+
+// Base64Decode decodes data encoded with MIME base64
+//
+// Like PHP the characters out of the base64 alphabet are skipped and the padding is optional.
+// If the optional strict is true, an error is returned for the characters out of the alphabet
+// except whitespace, for data after the padding and for the wrong padding.
+//
+// see http://php.net/manual/en/function.base64-decode.php
+func Base64Decode(str string, strict ...bool) (string, error) {
+	isStrict := len(strict) > 0 && strict[0]
+	res := make([]byte, 0, len(str)/4*3+3)
+	var acc uint
+	n, padding := 0, 0
+	for i := 0; i < len(str); i++ {
+		c := str[i]
+		if c == '=' {
+			padding++
+			continue
+		}
+		v := strings.IndexByte(base64Alphabet, c)
+		if v < 0 {
+			if !isStrict || c == ' ' || c == '\t' || c == '\r' || c == '\n' {
+				continue
+			}
+			return "", errors.New("invalid base64 character")
+		}
+		if isStrict && padding > 0 {
+			return "", errors.New("invalid base64 padding")
+		}
+		acc = acc<<6 | uint(v)
+		if n++; n%4 != 1 {
+			res = append(res, byte(acc>>(uint(4-n%4)*2%8)))
+		}
+	}
+	if isStrict && (n%4 == 1 || padding > 0 && (padding > 2 || (n+padding)%4 != 0)) {
+		return "", errors.New("invalid base64 padding")
+	}
+	return string(res), nil
+}
+
+const base64Alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+// Bin2hex convert binary data into hexadecimal representation
+//
+// see http://php.net/manual/en/function.bin2hex.php
+func Bin2hex(str string) string {
+	return hex.EncodeToString([]byte(str))
+}
+
+// Hex2bin decodes a hexadecimally encoded binary string
+//
+// see http://php.net/manual/en/function.hex2bin.php
+func Hex2bin(str string) (string, error) {
+	if len(str)%2 != 0 {
+		return "", errors.New("hexadecimal input string must have an even length")
+	}
+	res, err := hex.DecodeString(str)
+	if err != nil {
+		return "", errors.New("input string must be hexadecimal string")
+	}
+	return string(res), nil
+}
+
+// QuotedPrintableEncode convert a 8 bit string to a quoted-printable string
+//
+// Lines are soft broken at 76 characters and multi-byte UTF-8 characters are not split,
+// the same as PHP.
+//
+// see http://php.net/manual/en/function.quoted-printable-encode.php
+func QuotedPrintableEncode(str string) string {
+	const maxLine = 75
+	const digits = "0123456789ABCDEF"
+	var b strings.Builder
+	lp := 0
+	for i := 0; i < len(str); i++ {
+		c := str[i]
+		var next byte
+		if i+1 < len(str) {
+			next = str[i+1]
+		}
+		if c == '\r' && next == '\n' {
+			b.WriteString("\r\n")
+			i++
+			lp = 0
+			continue
+		}
+		if c < 0x20 || c >= 0x7f || c == '=' || (c == ' ' && next == '\r') {
+			lp += 3
+			if (lp > maxLine && c <= 0x7f) ||
+				(c > 0x7f && c <= 0xdf && lp+3 > maxLine) ||
+				(c > 0xdf && c <= 0xef && lp+6 > maxLine) ||
+				(c > 0xef && c <= 0xf4 && lp+9 > maxLine) {
+				b.WriteString("=\r\n")
+				lp = 3
+			}
+			b.WriteByte('=')
+			b.WriteByte(digits[c>>4])
+			b.WriteByte(digits[c&0xf])
+		} else {
+			if lp++; lp > maxLine {
+				b.WriteString("=\r\n")
+				lp = 1
+			}
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// QuotedPrintableDecode convert a quoted-printable string to an 8 bit string
+//
+// Like PHP an invalid "=" sequence is kept as it is, and "=" followed by optional spaces and a line
+// break is a soft line break.
+//
+// see http://php.net/manual/en/function.quoted-printable-decode.php
+func QuotedPrintableDecode(str string) string {
+	at := func(i int) byte {
+		if i < len(str) {
+			return str[i]
+		}
+		return 0
+	}
+	res := make([]byte, 0, len(str))
+	for i := 0; i < len(str); {
+		if str[i] != '=' {
+			res = append(res, str[i])
+			i++
+			continue
+		}
+		if isHexDigit(at(i+1)) && isHexDigit(at(i+2)) {
+			v, _ := strconv.ParseUint(str[i+1:i+3], 16, 8)
+			res = append(res, byte(v))
+			i += 3
+			continue
+		}
+		k := 1
+		for at(i+k) == ' ' || at(i+k) == '\t' {
+			k++
+		}
+		switch {
+		case i+k >= len(str):
+			i += k
+		case at(i+k) == '\r' && at(i+k+1) == '\n':
+			i += k + 2
+		case at(i+k) == '\r' || at(i+k) == '\n':
+			i += k + 1
+		default:
+			res = append(res, str[i])
+			i++
+		}
+	}
+	return string(res)
+}
+
+// isHexDigit checks if c is a hexadecimal digit
+func isHexDigit(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+// ConvertUuencode uuencode a string
+//
+// see http://php.net/manual/en/function.convert-uuencode.php
+func ConvertUuencode(str string) string {
+	if str == "" {
+		return ""
+	}
+	enc := func(c int) byte {
+		if c == 0 {
+			return '`'
+		}
+		return byte(c&077) + ' '
+	}
+	at := func(i int) int {
+		if i < len(str) {
+			return int(str[i])
+		}
+		return 0
+	}
+	c2 := func(s int) byte { return enc(at(s)<<4&060 | at(s+1)>>4&017) }
+	c3 := func(s int) byte { return enc(at(s+1)<<2&074 | at(s+2)>>6&03) }
+
+	var b strings.Builder
+	s, e, n := 0, len(str), 45
+	for s+3 < e {
+		ee := s + n
+		if ee > e {
+			ee = e
+			n = ee - s
+			ee = s + n/3*3
+		}
+		b.WriteByte(enc(n))
+		for ; s < ee; s += 3 {
+			b.WriteByte(enc(at(s) >> 2))
+			b.WriteByte(c2(s))
+			b.WriteByte(c3(s))
+			b.WriteByte(enc(at(s+2) & 077))
+		}
+		if n == 45 {
+			b.WriteByte('\n')
+		}
+	}
+	if s < e {
+		if n == 45 {
+			b.WriteByte(enc(e - s))
+			n = 0
+		}
+		b.WriteByte(enc(at(s) >> 2))
+		b.WriteByte(c2(s))
+		if e-s > 1 {
+			b.WriteByte(c3(s))
+		} else {
+			b.WriteByte(enc(0))
+		}
+		if e-s > 2 {
+			b.WriteByte(enc(at(s+2) & 077))
+		} else {
+			b.WriteByte(enc(0))
+		}
+	}
+	if n < 45 {
+		b.WriteByte('\n')
+	}
+	b.WriteByte(enc(0))
+	b.WriteByte('\n')
+	return b.String()
+}
+
+// StrRot13 perform the rot13 transform on a string
+//
+// see http://php.net/manual/en/function.str-rot13.php
+func StrRot13(str string) string {
+	res := []byte(str)
+	for i, c := range res {
+		switch {
+		case 'a' <= c && c <= 'z':
+			res[i] = 'a' + (c-'a'+13)%26
+		case 'A' <= c && c <= 'Z':
+			res[i] = 'A' + (c-'A'+13)%26
+		}
+	}
+	return string(res)
+}
+
+// BaseConvert convert a number between arbitrary bases
+//
+// The bases are between 2 and 36, digits above 9 are letters case-insensitively. Like PHP the invalid
+// characters are ignored, the prefixes "0x", "0o" and "0b" are allowed for the bases 16, 8 and 2,
+// and numbers overflowing int64 lose precision as float64.
+// .eg BaseConvert("ff", 16, 2) returns "11111111"
+//
+// see http://php.net/manual/en/function.base-convert.php
+func BaseConvert(number string, fromBase, toBase int) (string, error) {
+	if fromBase < 2 || fromBase > 36 {
+		return "", errors.New("from base must be between 2 and 36 (inclusive)")
+	}
+	if toBase < 2 || toBase > 36 {
+		return "", errors.New("to base must be between 2 and 36 (inclusive)")
+	}
+
+	s := strings.TrimFunc(number, func(r rune) bool { return r < utf8.RuneSelf && isSpace(byte(r)) })
+	if len(s) >= 2 && s[0] == '0' {
+		switch {
+		case fromBase == 16 && (s[1] == 'x' || s[1] == 'X'),
+			fromBase == 8 && (s[1] == 'o' || s[1] == 'O'),
+			fromBase == 2 && (s[1] == 'b' || s[1] == 'B'):
+			s = s[2:]
+		}
+	}
+
+	const maxInt = uint64(1<<63 - 1)
+	base := uint64(fromBase)
+	var num uint64
+	var fnum float64
+	isFloat := false
+	for i := 0; i < len(s); i++ {
+		c := uint64(36)
+		switch ch := s[i]; {
+		case '0' <= ch && ch <= '9':
+			c = uint64(ch - '0')
+		case 'A' <= ch && ch <= 'Z':
+			c = uint64(ch-'A') + 10
+		case 'a' <= ch && ch <= 'z':
+			c = uint64(ch-'a') + 10
+		}
+		if c >= base {
+			continue
+		}
+		if !isFloat {
+			if num < maxInt/base || (num == maxInt/base && c <= maxInt%base) {
+				num = num*base + c
+				continue
+			}
+			fnum, isFloat = float64(num), true
+		}
+		fnum = fnum*float64(base) + float64(c)
+	}
+
+	const digits = "0123456789abcdefghijklmnopqrstuvwxyz"
+	if !isFloat {
+		return strconv.FormatUint(num, toBase), nil
+	}
+	if math.IsInf(fnum, 0) {
+		return "", errors.New("number too large")
+	}
+	var res []byte
+	for fnum = math.Floor(fnum); ; {
+		res = append(res, digits[int(math.Mod(fnum, float64(toBase)))])
+		if fnum /= float64(toBase); fnum < 1 {
+			break
+		}
+	}
+	for i, j := 0, len(res)-1; i < j; i, j = i+1, j-1 {
+		res[i], res[j] = res[j], res[i]
+	}
+	return string(res), nil
+}
+
+// formatFloat converts f to string like PHP's php_gcvt with precision significant digits,
+// .eg 0.1, 100, 1.0E+25 and 1.0E-5. The precision -1 is the shortest representation
+// that round trips, like PHP's serialize_precision -1.
+func formatFloat(f float64, precision int) string {
+	switch {
+	case math.IsNaN(f):
+		return "NAN"
+	case math.IsInf(f, 1):
+		return "INF"
+	case math.IsInf(f, -1):
+		return "-INF"
+	}
+
+	ndigit, prec := precision, precision-1
+	if precision < 0 {
+		ndigit, prec = 17, -1
+	} else if precision == 0 {
+		ndigit, prec = 1, 0
+	}
+	s := strconv.FormatFloat(math.Abs(f), 'e', prec, 64)
+	mant, exp, _ := strings.Cut(s, "e")
+	digits := strings.TrimRight(strings.Replace(mant, ".", "", 1), "0")
+	decpt, _ := strconv.Atoi(exp)
+	decpt++
+	if digits == "" {
+		digits, decpt = "0", 1
+	}
+
+	var b strings.Builder
+	if math.Signbit(f) {
+		b.WriteByte('-')
+	}
+	switch {
+	case (decpt < 0 && decpt < -3) || (decpt >= 0 && decpt > ndigit):
+		b.WriteByte(digits[0])
+		b.WriteByte('.')
+		if len(digits) == 1 {
+			b.WriteByte('0')
+		} else {
+			b.WriteString(digits[1:])
+		}
+		b.WriteByte('E')
+		if decpt--; decpt < 0 {
+			b.WriteByte('-')
+			decpt = -decpt
+		} else {
+			b.WriteByte('+')
+		}
+		b.WriteString(strconv.Itoa(decpt))
+	case decpt <= 0:
+		b.WriteString("0.")
+		b.WriteString(strings.Repeat("0", -decpt))
+		b.WriteString(digits)
+	case len(digits) <= decpt:
+		b.WriteString(digits)
+		b.WriteString(strings.Repeat("0", decpt-len(digits)))
+	default:
+		b.WriteString(digits[:decpt])
+		b.WriteByte('.')
+		b.WriteString(digits[decpt:])
+	}
+	return b.String()
+}
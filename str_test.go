@@ -0,0 +1,776 @@
+package php
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestHtmlspecialchars(t *testing.T) {
+	tests := []struct {
+		str          string
+		flags        int
+		doubleEncode bool
+		want         string
+	}{
+		{"<a href='test'>Test</a>", EntQuotes, true, "&lt;a href=&#039;test&#039;&gt;Test&lt;/a&gt;"},
+		{"<a href='test'>Test</a>", EntQuotes | EntHTML5, true, "&lt;a href=&apos;test&apos;&gt;Test&lt;/a&gt;"},
+		{`"'`, EntCompat, true, `&quot;'`},
+		{`"'`, EntNoQuotes, true, `"'`},
+		{`'`, EntQuotes | EntXML1, true, "&apos;"},
+		{"&amp; &foo; &#38; &", EntQuotes, false, "&amp; &amp;foo; &#38; &amp;"},
+		{"&amp;", EntQuotes, true, "&amp;amp;"},
+		{"\x80abc", EntQuotes, true, ""},
+		{"\x80abc", EntQuotes | EntSubstitute, true, "\uFFFDabc"},
+		{"\x80abc", EntQuotes | EntIgnore, true, "abc"},
+	}
+	for _, test := range tests {
+		if got := Htmlspecialchars(test.str, test.flags, test.doubleEncode); got != test.want {
+			t.Errorf("Htmlspecialchars(%q, %d, %v) = %q, want %q", test.str, test.flags, test.doubleEncode, got, test.want)
+		}
+	}
+}
+
+func TestHtmlentities(t *testing.T) {
+	tests := []struct {
+		str   string
+		flags int
+		want  string
+	}{
+		{"café <b>", EntQuotes, "caf&eacute; &lt;b&gt;"},
+		{"© 2024 €", EntQuotes | EntHTML5, "&copy; 2024 &euro;"},
+		{"é'", EntQuotes | EntXML1, "é&apos;"},
+		{"→", EntQuotes, "&rarr;"},
+	}
+	for _, test := range tests {
+		if got := Htmlentities(test.str, test.flags, true); got != test.want {
+			t.Errorf("Htmlentities(%q, %d) = %q, want %q", test.str, test.flags, got, test.want)
+		}
+	}
+}
+
+func TestHtmlDecode(t *testing.T) {
+	tests := []struct {
+		str   string
+		flags int
+		want  string
+	}{
+		{"I&#039;ll &quot;walk&quot; the &lt;b&gt;dog&lt;/b&gt; now", EntQuotes, `I'll "walk" the <b>dog</b> now`},
+		{"&eacute;&euro;&hellip;", EntQuotes, "é€…"},
+		{"&apos;", EntQuotes, "&apos;"},
+		{"&apos;", EntQuotes | EntHTML5, "'"},
+		{"&#x41;&#65;&#0;", EntQuotes, "AA&#0;"},
+		{"&amp;lt;", EntQuotes, "&lt;"},
+		{"&quot;&#039;", EntNoQuotes, "&quot;&#039;"},
+		{"&quot;&#039;", EntCompat, `"&#039;`},
+	}
+	for _, test := range tests {
+		if got := HtmlEntityDecode(test.str, test.flags); got != test.want {
+			t.Errorf("HtmlEntityDecode(%q, %d) = %q, want %q", test.str, test.flags, got, test.want)
+		}
+	}
+
+	special := []struct {
+		str   string
+		flags int
+		want  string
+	}{
+		{"<p>this -&gt; &quot;</p>", EntCompat, `<p>this -> "</p>`},
+		{"<p>this -&gt; &quot;</p>", EntNoQuotes, "<p>this -> &quot;</p>"},
+		{"&eacute; &amp;", EntQuotes, "&eacute; &"},
+	}
+	for _, test := range special {
+		if got := HtmlspecialcharsDecode(test.str, test.flags); got != test.want {
+			t.Errorf("HtmlspecialcharsDecode(%q, %d) = %q, want %q", test.str, test.flags, got, test.want)
+		}
+	}
+}
+
+func TestStripTags(t *testing.T) {
+	text := `<p>Test paragraph.</p><!-- Comment --> <a href="#fragment">Other text</a>`
+	tests := []struct {
+		str     string
+		allowed []string
+		want    string
+	}{
+		{text, nil, "Test paragraph. Other text"},
+		{text, []string{"<p><a>"}, `<p>Test paragraph.</p> <a href="#fragment">Other text</a>`},
+		{text, []string{"p", "a"}, `<p>Test paragraph.</p> <a href="#fragment">Other text</a>`},
+		{"<?php echo 1; ?>text", nil, "text"},
+		{"a < b and c > d", nil, "a < b and c > d"},
+		{"<b>bold</b><br/>", []string{"<br>"}, "bold<br/>"},
+		{`<a title="x>y">link</a>`, nil, "link"},
+	}
+	for _, test := range tests {
+		if got := StripTags(test.str, test.allowed...); got != test.want {
+			t.Errorf("StripTags(%q, %q) = %q, want %q", test.str, test.allowed, got, test.want)
+		}
+	}
+}
+
+func TestTrim(t *testing.T) {
+	tests := []struct {
+		str, charlist string
+		trim, ltrim   string
+		rtrim         string
+	}{
+		{"\t\n Hello World \x00\x0B", "", "Hello World", "Hello World \x00\x0B", "\t\n Hello World"},
+		{"Hello World", "Hdle", "o Wor", "o World", "Hello Wor"},
+		{"abcXYZabc", "a..z", "XYZ", "XYZabc", "abcXYZ"},
+		{"\x01\x1fbinary\x1f", "\x00..\x1F", "binary", "binary\x1f", "\x01\x1fbinary"},
+		{"1.500", "0", "1.5", "1.500", "1.5"},
+		{"..a..", "a..", "", "", ""},
+		{"z.a", "z..a", "", "", ""},
+	}
+	for _, test := range tests {
+		charlist := []string{test.charlist}
+		if test.charlist == "" {
+			charlist = nil
+		}
+		if got := Trim(test.str, charlist...); got != test.trim {
+			t.Errorf("Trim(%q, %q) = %q, want %q", test.str, test.charlist, got, test.trim)
+		}
+		if got := Ltrim(test.str, charlist...); got != test.ltrim {
+			t.Errorf("Ltrim(%q, %q) = %q, want %q", test.str, test.charlist, got, test.ltrim)
+		}
+		if got := Rtrim(test.str, charlist...); got != test.rtrim {
+			t.Errorf("Rtrim(%q, %q) = %q, want %q", test.str, test.charlist, got, test.rtrim)
+		}
+	}
+}
+
+func TestMbTrim(t *testing.T) {
+	tests := []struct {
+		str, charlist, want string
+	}{
+		{"\u3000 Hello\u00a0\u2028", "", "Hello"},
+		{"абвгдба", "а..в", "гд"},
+		{"世界和平世", "世", "界和平"},
+		{"\t\n ", "", ""},
+	}
+	for _, test := range tests {
+		charlist := []string{test.charlist}
+		if test.charlist == "" {
+			charlist = nil
+		}
+		if got := MbTrim(test.str, charlist...); got != test.want {
+			t.Errorf("MbTrim(%q, %q) = %q, want %q", test.str, test.charlist, got, test.want)
+		}
+	}
+}
+
+func TestAddcslashes(t *testing.T) {
+	tests := []struct {
+		str, charlist, want string
+	}{
+		{"foo[bar]", "A..Z", "foo[bar]"},
+		{"foo[bar]", "A..z", `\f\o\o\[\b\a\r\]`},
+		{"zoo['.']", "z..A", `\zoo['\.']`},
+		{"a\nb\x00\xff\t", "\x00..\x1f\x7f..\xff", `a\nb\000\377\t`},
+		{"\a\b\v\f\r", "\x00..\x1f", `\a\b\v\f\r`},
+	}
+	for _, test := range tests {
+		if got := Addcslashes(test.str, test.charlist); got != test.want {
+			t.Errorf("Addcslashes(%q, %q) = %q, want %q", test.str, test.charlist, got, test.want)
+		}
+	}
+}
+
+func TestMbStrwidth(t *testing.T) {
+	tests := []struct {
+		str  string
+		want int
+	}{
+		{"", 0},
+		{"Hello", 5},
+		{"世界", 4},
+		{"Hello世界", 9},
+		{"ｈｅｌｌｏ", 10},
+		{"こんにちは", 10},
+		{"😀", 2},
+		{"Go语言🙂!", 9},
+		{"e\u0301", 2},
+	}
+	for _, test := range tests {
+		if got := MbStrwidth(test.str); got != test.want {
+			t.Errorf("MbStrwidth(%q) = %d, want %d", test.str, got, test.want)
+		}
+	}
+}
+
+func TestMbStrimwidth(t *testing.T) {
+	tests := []struct {
+		str          string
+		start, width int
+		marker       string
+		want         string
+	}{
+		{"Hello World", 0, 10, "...", "Hello W..."},
+		{"Hello World", 0, 11, "...", "Hello World"},
+		{"Hello世界", 0, 9, "", "Hello世界"},
+		{"Hello世界", 0, 8, "", "Hello世"},
+		{"Hello世界", 0, 8, "..", "Hello.."},
+		{"中文字符串", 0, 5, "…", "中文…"},
+		{"中文字符串", 0, 6, "..", "中文.."},
+		{"ab中文", 0, 4, ".", "ab."},
+		{"ab中文", 0, 5, ".", "ab中."},
+		{"😀😀😀", 0, 5, "...", "😀..."},
+		{"😀😀😀", 0, 4, "...", "..."},
+		{"a😀b", 0, 3, "", "a😀"},
+		{"Go语言🙂!", 2, 6, "", "语言🙂"},
+		{"Hello世界", -2, 4, "", "世界"},
+		{"Hello世界", 0, -2, "", "Hello世"},
+		{"世界", 0, 1, "...", "..."},
+		{"世界", 3, 4, "", ""},
+	}
+	for _, test := range tests {
+		if got := MbStrimwidth(test.str, test.start, test.width, test.marker); got != test.want {
+			t.Errorf("MbStrimwidth(%q, %d, %d, %q) = %q, want %q", test.str, test.start, test.width, test.marker, got, test.want)
+		}
+	}
+}
+
+func TestReplace(t *testing.T) {
+	tests := []struct {
+		search, replace []string
+		subject         string
+		want            string
+		count           int
+	}{
+		{[]string{"a", "b"}, []string{"b", "c"}, "ab", "cc", 3},
+		{[]string{"bc", "ab"}, []string{"X", "Y"}, "abc", "aX", 1},
+		{[]string{"ab", "bc"}, []string{"X", "Y"}, "abcbc", "XcY", 2},
+		{[]string{"a", "a"}, []string{"b", "c"}, "aa", "bb", 2},
+		{[]string{"b", "ac"}, []string{"", "Z"}, "abc", "Z", 2},
+		{[]string{"&", "<", ">"}, []string{"&amp;", "&lt;", "&gt;"}, "<a & b>", "&lt;a &amp; b&gt;", 3},
+		{[]string{"<", "&"}, []string{"&lt;", "&amp;"}, "<&", "&amp;lt;&amp;", 3},
+		{[]string{"aa", "a"}, []string{"1", "2"}, "aaa", "12", 2},
+		{[]string{"", "x"}, []string{"y", "z"}, "xx", "zz", 2},
+	}
+	for _, test := range tests {
+		count := 0
+		if got := Replace(test.search, test.replace, test.subject, &count); got != test.want || count != test.count {
+			t.Errorf("Replace(%q, %q, %q) = %q, %d, want %q, %d", test.search, test.replace, test.subject, got, count, test.want, test.count)
+		}
+	}
+
+	count := 0
+	if got := Ireplace([]string{"STRASSE", "ǅ"}, []string{"street", "dz"}, "Strasse ǆ", &count); got != "street dz" || count != 2 {
+		t.Errorf("Ireplace = %q, %d", got, count)
+	}
+	if got := Ireplace([]string{"É", "x"}, "-", "éÉxX"); got != "----" {
+		t.Errorf("Ireplace = %q", got)
+	}
+}
+
+// replaceOneByOne is the reference of Replace and Ireplace applying the pairs one after another
+func replaceOneByOne(search, replace []string, subject string, ignoreCase bool) (string, int) {
+	count := 0
+	for i, s := range search {
+		if s == "" {
+			continue
+		}
+		if !ignoreCase {
+			count += strings.Count(subject, s)
+			subject = strings.Replace(subject, s, replace[i], -1)
+			continue
+		}
+		var b strings.Builder
+		rest := subject
+		for {
+			start, end := indexFold(rest, foldString(s))
+			if start < 0 {
+				break
+			}
+			b.WriteString(rest[:start])
+			b.WriteString(replace[i])
+			rest = rest[end:]
+			count++
+		}
+		b.WriteString(rest)
+		subject = b.String()
+	}
+	return subject, count
+}
+
+func TestReplaceOneByOne(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	alphabet := []string{"a", "b", "A", "B", "é", "É", ""}
+	word := func(n int) string {
+		var b strings.Builder
+		for i := rnd.Intn(n + 1); i > 0; i-- {
+			b.WriteString(alphabet[rnd.Intn(len(alphabet))])
+		}
+		return b.String()
+	}
+	for i := 0; i < 20000; i++ {
+		search, replace := make([]string, 1+rnd.Intn(4)), make([]string, 4)
+		for j := range search {
+			search[j], replace[j] = word(3), word(3)
+		}
+		subject := word(12)
+		for _, ignoreCase := range []bool{false, true} {
+			want, wantCount := replaceOneByOne(search, replace, subject, ignoreCase)
+			count := 0
+			got := replaceSlice(search, replace[:len(search)], []string{subject}, ignoreCase, &count)[0]
+			if got != want || count != wantCount {
+				t.Fatalf("replace(%q, %q, %q, %v) = %q, %d, want %q, %d", search, replace[:len(search)], subject, ignoreCase, got, count, want, wantCount)
+			}
+		}
+	}
+}
+
+func TestNewStrtr(t *testing.T) {
+	pairs := map[string]string{"Hi": "Hello", "Hello": "Hi", "a": "A", "ab": "X", "": "empty"}
+	r := NewStrtr(pairs)
+	tests := []struct {
+		str, want string
+	}{
+		{"Hi all, Hello", "Hello All, Hi"},
+		{"abc", "Xc"},
+		{"", ""},
+		{"xyz", "xyz"},
+	}
+	for _, test := range tests {
+		if got := r.Replace(test.str); got != test.want {
+			t.Errorf("Replace(%q) = %q, want %q", test.str, got, test.want)
+		}
+		if got := StrtrPairs(test.str, pairs); got != test.want {
+			t.Errorf("StrtrPairs(%q) = %q, want %q", test.str, got, test.want)
+		}
+	}
+	if got := NewStrtr(nil).Replace("abc"); got != "abc" {
+		t.Errorf("Replace with no pairs = %q", got)
+	}
+}
+
+func TestBase64Decode(t *testing.T) {
+	tests := []struct {
+		str    string
+		strict bool
+		want   string
+		err    bool
+	}{
+		{"VGhpcyBpcyBhbiBlbmNvZGVkIHN0cmluZw==", false, "This is an encoded string", false},
+		{"aGVsbG8=", true, "hello", false},
+		{"aGVsbG8", true, "hello", false},
+		{"aGVs bG8=\n", true, "hello", false},
+		{"aGVs*bG8=", false, "hello", false},
+		{"aGVs*bG8=", true, "", true},
+		{"aGVsbG8=x", false, "hello1", false},
+		{"aGVsbG8=x", true, "", true},
+		{"aGVsbG8==", true, "", true},
+		{"a", true, "", true},
+		{"", true, "", false},
+	}
+	for _, test := range tests {
+		got, err := Base64Decode(test.str, test.strict)
+		if (err != nil) != test.err || got != test.want {
+			t.Errorf("Base64Decode(%q, %v) = %q, %v, want %q", test.str, test.strict, got, err, test.want)
+		}
+	}
+	if got := Base64Encode("This is an encoded string"); got != "VGhpcyBpcyBhbiBlbmNvZGVkIHN0cmluZw==" {
+		t.Errorf("Base64Encode = %q", got)
+	}
+}
+
+func TestHex2bin(t *testing.T) {
+	if got, err := Hex2bin("6578616d706c65206865782064617461"); err != nil || got != "example hex data" {
+		t.Errorf("Hex2bin = %q, %v", got, err)
+	}
+	for _, str := range []string{"abc", "zz"} {
+		if _, err := Hex2bin(str); err == nil {
+			t.Errorf("Hex2bin(%q) expected an error", str)
+		}
+	}
+	if got := Bin2hex("example hex data"); got != "6578616d706c65206865782064617461" {
+		t.Errorf("Bin2hex = %q", got)
+	}
+}
+
+func TestQuotedPrintable(t *testing.T) {
+	encode := []struct {
+		str, want string
+	}{
+		{"é=", "=C3=A9=3D"},
+		{"a\r\nb", "a\r\nb"},
+		{"a \r\nb", "a=20\r\nb"},
+		{strings.Repeat("a", 80), strings.Repeat("a", 75) + "=\r\n" + strings.Repeat("a", 5)},
+		{strings.Repeat("a", 73) + "é", strings.Repeat("a", 73) + "=\r\n=C3=A9"},
+	}
+	for _, test := range encode {
+		if got := QuotedPrintableEncode(test.str); got != test.want {
+			t.Errorf("QuotedPrintableEncode(%q) = %q, want %q", test.str, got, test.want)
+		}
+	}
+
+	decode := []struct {
+		str, want string
+	}{
+		{"=C3=A9=3d", "é="},
+		{"soft=\r\nbreak", "softbreak"},
+		{"a=  \nb", "ab"},
+		{"=ZZ", "=ZZ"},
+		{"a=", "a"},
+	}
+	for _, test := range decode {
+		if got := QuotedPrintableDecode(test.str); got != test.want {
+			t.Errorf("QuotedPrintableDecode(%q) = %q, want %q", test.str, got, test.want)
+		}
+	}
+}
+
+func TestConvertUuencode(t *testing.T) {
+	tests := []struct {
+		str, want string
+	}{
+		{"", ""},
+		{"a", "!80``\n`\n"},
+		{"abc", "#86)C\n`\n"},
+		{"test\ntext text text\r\n", "5=&5S=`IT97AT('1E>'0@=&5X=`T*\n`\n"},
+		{strings.Repeat("y", 50), "M" + strings.Repeat(">7EY", 15) + "\n%>7EY>7D`\n`\n"},
+	}
+	for _, test := range tests {
+		if got := ConvertUuencode(test.str); got != test.want {
+			t.Errorf("ConvertUuencode(%q) = %q, want %q", test.str, got, test.want)
+		}
+	}
+}
+
+func TestStrRot13(t *testing.T) {
+	if got := StrRot13("Hello, World!"); got != "Uryyb, Jbeyq!" {
+		t.Errorf("StrRot13 = %q", got)
+	}
+}
+
+func TestBaseConvert(t *testing.T) {
+	tests := []struct {
+		number         string
+		fromBase, base int
+		want           string
+	}{
+		{"a37334", 16, 2, "101000110111001100110100"},
+		{"ff", 16, 10, "255"},
+		{"0xff", 16, 10, "255"},
+		{"0b101", 2, 10, "5"},
+		{"ZZ", 36, 10, "1295"},
+		{"1g", 16, 10, "1"},
+		{"-15", 10, 2, "1111"},
+		{"255", 10, 16, "ff"},
+		{"", 10, 2, "0"},
+	}
+	for _, test := range tests {
+		got, err := BaseConvert(test.number, test.fromBase, test.base)
+		if err != nil || got != test.want {
+			t.Errorf("BaseConvert(%q, %d, %d) = %q, %v, want %q", test.number, test.fromBase, test.base, got, err, test.want)
+		}
+	}
+	if _, err := BaseConvert("1", 1, 10); err == nil {
+		t.Error("BaseConvert from base 1 expected an error")
+	}
+	if _, err := BaseConvert("1", 10, 37); err == nil {
+		t.Error("BaseConvert to base 37 expected an error")
+	}
+}
+
+func TestOrd(t *testing.T) {
+	tests := []struct {
+		character string
+		want      rune
+	}{
+		{"", 0},
+		{"A", 'A'},
+		{"abc", 'a'},
+		{"\xff", 0xff},
+		{"é", 0xc3},
+	}
+	for _, test := range tests {
+		if got := Ord(test.character); got != test.want {
+			t.Errorf("Ord(%q) = %d, want %d", test.character, got, test.want)
+		}
+	}
+}
+
+func TestStrposOffset(t *testing.T) {
+	const foo = "0123456789a123456789b123456789c"
+	tests := []struct {
+		f                string
+		haystack, needle string
+		offset           int
+		want             int
+	}{
+		{"Strpos", "hello world", "o", 0, 4},
+		{"Strpos", "hello world", "o", 5, 7},
+		{"Strpos", "hello world", "o", -5, 7},
+		{"Strpos", "hello world", "o", -3, -1},
+		{"Strpos", "hello world", "", -3, 8},
+		{"Strpos", "abc", "a", 4, -1},
+		{"Strpos", "abc", "a", -4, -1},
+		{"Strpos", "日本語日本語", "本", 2, 4},
+		{"Strpos", "日本語", "語", -1, 2},
+		{"Strrpos", foo, "7", -5, 17},
+		{"Strrpos", foo, "7", 20, 27},
+		{"Strrpos", foo, "7", 28, -1},
+		{"Strrpos", foo, "c", -1, 30},
+		{"Strrpos", "日本語日本語", "日本", -3, 3},
+		{"Strrpos", "日本語日本語", "日本", -4, 0},
+		{"Stripos", "ÄBC äbc", "äb", 1, 4},
+		{"Stripos", "ABC", "b", -2, 1},
+		{"Strripos", "aXbxc", "X", 0, 3},
+		{"Strripos", "aXbxc", "X", -3, 1},
+	}
+	funcs := map[string]func(string, string, ...int) int{
+		"Strpos": Strpos, "Strrpos": Strrpos, "Stripos": Stripos, "Strripos": Strripos,
+	}
+	for _, test := range tests {
+		if got := funcs[test.f](test.haystack, test.needle, test.offset); got != test.want {
+			t.Errorf("%s(%q, %q, %d) = %d, want %d", test.f, test.haystack, test.needle, test.offset, got, test.want)
+		}
+	}
+}
+
+func TestSubstrCount(t *testing.T) {
+	const text = "This is a test"
+	tests := []struct {
+		haystack, needle string
+		params           []int
+		want             int
+	}{
+		{text, "is", nil, 2},
+		{text, "is", []int{3}, 1},
+		{text, "is", []int{3, 3}, 0},
+		{text, "is", []int{-9}, 1},
+		{text, "is", []int{5, 10}, 0},
+		{text, "is", []int{0, -9}, 1},
+		{"gcdgcdgcd", "gcdgcd", nil, 1},
+		{"日本語日本語", "本", []int{2}, 1},
+		{text, "", nil, 0},
+	}
+	for _, test := range tests {
+		if got := SubstrCount(test.haystack, test.needle, test.params...); got != test.want {
+			t.Errorf("SubstrCount(%q, %q, %v) = %d, want %d", test.haystack, test.needle, test.params, got, test.want)
+		}
+	}
+
+	if !StrContains("abc", "") || !StrContains("abc", "bc") || StrContains("abc", "d") {
+		t.Error("StrContains is wrong")
+	}
+	if !StrStartsWith("abc", "ab") || StrStartsWith("abc", "bc") || !StrEndsWith("abc", "bc") || StrEndsWith("abc", "ab") {
+		t.Error("StrStartsWith or StrEndsWith is wrong")
+	}
+}
+
+// substrRunes is the reference of Substr converting str to []rune
+func substrRunes(str string, start, length int) string {
+	rs := []rune(str)
+	rl := len(rs)
+	if rl == 0 {
+		return ""
+	}
+	if start < 0 {
+		start = rl + start
+	}
+	if start < 0 {
+		start = 0
+	}
+	if start > rl-1 {
+		return ""
+	}
+	end := rl
+	if length < 0 {
+		end = rl + length
+	} else if length > 0 {
+		end = start + length
+	}
+	if end < 0 || start >= end {
+		return ""
+	}
+	if end > rl {
+		end = rl
+	}
+	return string(rs[start:end])
+}
+
+// strlenRunes is the reference of Strlen
+func strlenRunes(str string) int {
+	return len([]rune(str))
+}
+
+// strposRunes is the reference of Strpos and Strrpos comparing []rune
+func strposRunes(haystack, needle string, offset int, reverse bool) int {
+	hs, ns := []rune(haystack), []rune(needle)
+	length := len(hs)
+	if offset > length || offset < -length {
+		return -1
+	}
+	match := func(i int) bool {
+		for j, r := range ns {
+			if hs[i+j] != r {
+				return false
+			}
+		}
+		return true
+	}
+	if !reverse {
+		if offset < 0 {
+			offset += length
+		}
+		for i := offset; i+len(ns) <= length; i++ {
+			if match(i) {
+				return i
+			}
+		}
+		return -1
+	}
+	maxStart := length - len(ns)
+	if offset < 0 {
+		if length+offset < maxStart {
+			maxStart = length + offset
+		}
+		offset = 0
+	}
+	for i := maxStart; i >= offset; i-- {
+		if match(i) {
+			return i
+		}
+	}
+	return -1
+}
+
+// lcfirstRunes and ucfirstRunes are the references of Lcfirst and Ucfirst
+func lcfirstRunes(str string) string {
+	return strings.ToLower(substrRunes(str, 0, 1)) + substrRunes(str, 1, 0)
+}
+
+func ucfirstRunes(str string) string {
+	return strings.ToUpper(substrRunes(str, 0, 1)) + substrRunes(str, 1, 0)
+}
+
+func FuzzSubstr(f *testing.F) {
+	f.Add("Hello世界", 0, 0)
+	f.Add("Hello世界", -2, 1)
+	f.Add("Hello世界", 3, -1)
+	f.Add("a\xffb\xe4\xb8c", 1, 2)
+	f.Add("", 0, 1)
+	f.Fuzz(func(t *testing.T, str string, start, length int) {
+		if got, want := Substr(str, start, length), substrRunes(str, start, length); got != want {
+			t.Errorf("Substr(%q, %d, %d) = %q, want %q", str, start, length, got, want)
+		}
+	})
+}
+
+func FuzzStrlen(f *testing.F) {
+	f.Add("Hello世界")
+	f.Add("a\xffb\xe4\xb8")
+	f.Fuzz(func(t *testing.T, str string) {
+		if got, want := Strlen(str), strlenRunes(str); got != want {
+			t.Errorf("Strlen(%q) = %d, want %d", str, got, want)
+		}
+	})
+}
+
+func FuzzStrpos(f *testing.F) {
+	f.Add("Hello世界世界", "世", 0)
+	f.Add("Hello世界世界", "世界", -3)
+	f.Add("aaa", "", 2)
+	f.Add("abcabc", "c", -1)
+	f.Fuzz(func(t *testing.T, haystack, needle string, offset int) {
+		// the references compare runes, invalid bytes would all be U+FFFD
+		if !utf8.ValidString(haystack) || !utf8.ValidString(needle) {
+			t.Skip()
+		}
+		if got, want := Strpos(haystack, needle, offset), strposRunes(haystack, needle, offset, false); got != want {
+			t.Errorf("Strpos(%q, %q, %d) = %d, want %d", haystack, needle, offset, got, want)
+		}
+		if got, want := Strrpos(haystack, needle, offset), strposRunes(haystack, needle, offset, true); got != want {
+			t.Errorf("Strrpos(%q, %q, %d) = %d, want %d", haystack, needle, offset, got, want)
+		}
+	})
+}
+
+func FuzzUcfirst(f *testing.F) {
+	f.Add("hello")
+	f.Add("Élan")
+	f.Add("ǆx")
+	f.Add("\xffa")
+	f.Add("")
+	f.Fuzz(func(t *testing.T, str string) {
+		if got, want := Lcfirst(str), lcfirstRunes(str); got != want {
+			t.Errorf("Lcfirst(%q) = %q, want %q", str, got, want)
+		}
+		if got, want := Ucfirst(str), ucfirstRunes(str); got != want {
+			t.Errorf("Ucfirst(%q) = %q, want %q", str, got, want)
+		}
+	})
+}
+
+// benchText is a mixed ASCII and CJK text of the benchmarks
+var benchText = strings.Repeat("Hello, 世界! The quick brown fox 跳过了懒狗. ", 40)
+
+func BenchmarkSubstr(b *testing.B) {
+	b.Run("utf8", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Substr(benchText, 500, 100)
+		}
+	})
+	b.Run("runes", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			substrRunes(benchText, 500, 100)
+		}
+	})
+}
+
+func BenchmarkStrlen(b *testing.B) {
+	b.Run("utf8", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Strlen(benchText)
+		}
+	})
+	b.Run("runes", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			strlenRunes(benchText)
+		}
+	})
+}
+
+func BenchmarkStrpos(b *testing.B) {
+	b.Run("utf8", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Strpos(benchText, "懒狗", 100)
+		}
+	})
+	b.Run("runes", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			strposRunes(benchText, "懒狗", 100, false)
+		}
+	})
+}
+
+func BenchmarkStrrpos(b *testing.B) {
+	b.Run("utf8", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Strrpos(benchText, "Hello", -100)
+		}
+	})
+	b.Run("runes", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			strposRunes(benchText, "Hello", -100, true)
+		}
+	})
+}
+
+func BenchmarkUcfirst(b *testing.B) {
+	b.Run("utf8", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Ucfirst(benchText)
+			Lcfirst(benchText)
+		}
+	})
+	b.Run("runes", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ucfirstRunes(benchText)
+			lcfirstRunes(benchText)
+		}
+	})
+}
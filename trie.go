@@ -0,0 +1,61 @@
+package php
+
+// trie is a byte-wise prefix tree of keys, it finds the longest key at a position of a
+// string in a single pass no matter how many keys there are
+type trie struct {
+	nodes []trieNode
+}
+
+// trieNode is a node of trie
+type trieNode struct {
+	next  map[byte]int32
+	value int32 // index of the key which ends at this node, -1 if none
+}
+
+// newTrie returns a trie with only the root node
+func newTrie() *trie {
+	return &trie{nodes: []trieNode{{value: -1}}}
+}
+
+// insert adds key to the trie, value is returned when key is found
+func (t *trie) insert(key string, value int) {
+	n := int32(0)
+	for i := 0; i < len(key); i++ {
+		next, ok := t.nodes[n].next[key[i]]
+		if !ok {
+			next = int32(len(t.nodes))
+			t.nodes = append(t.nodes, trieNode{value: -1})
+			if t.nodes[n].next == nil {
+				t.nodes[n].next = make(map[byte]int32)
+			}
+			t.nodes[n].next[key[i]] = next
+		}
+		n = next
+	}
+	t.nodes[n].value = int32(value)
+}
+
+// step moves from node n along the byte c, it returns -1 if there is no such edge
+func (t *trie) step(n int32, c byte) int32 {
+	next, ok := t.nodes[n].next[c]
+	if !ok {
+		return -1
+	}
+	return next
+}
+
+// longest returns the value and the length of the longest key which is a prefix of s,
+// value is -1 if there is none
+func (t *trie) longest(s string) (value, length int) {
+	value = -1
+	n := int32(0)
+	for i := 0; i < len(s); i++ {
+		if n = t.step(n, s[i]); n < 0 {
+			break
+		}
+		if v := t.nodes[n].value; v >= 0 {
+			value, length = int(v), i+1
+		}
+	}
+	return value, length
+}
@@ -0,0 +1,569 @@
+package php
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Encoding types of HttpBuildQuery, the same as PHP's PHP_QUERY_* constants
+const (
+	// PhpQueryRFC1738 encodes spaces as "+", like Urlencode
+	PhpQueryRFC1738 int = 1
+	// PhpQueryRFC3986 encodes spaces as "%20", like Rawurlencode
+	PhpQueryRFC3986 int = 2
+)
+
+// Components of ParseUrlComponent, the same as PHP's PHP_URL_* constants
+const (
+	PhpUrlScheme int = iota
+	PhpUrlHost
+	PhpUrlPort
+	PhpUrlUser
+	PhpUrlPass
+	PhpUrlPath
+	PhpUrlQuery
+	PhpUrlFragment
+)
+
+var urlComponents = [...]string{"scheme", "host", "port", "user", "pass", "path", "query", "fragment"}
+
+// Urlencode URL-encodes string
+//
+// Like PHP, letters, digits and "-_." are kept, spaces are encoded as "+" and the others
+// including "~" as %XX.
+//
+// see http://php.net/manual/en/function.urlencode.php
+func Urlencode(str string) string {
+	return urlEncode(str, false)
+}
+
+// Rawurlencode URL-encode according to RFC 3986
+//
+// Letters, digits and "-_.~" are kept, the others including spaces are encoded as %XX.
+//
+// see http://php.net/manual/en/function.rawurlencode.php
+func Rawurlencode(str string) string {
+	return urlEncode(str, true)
+}
+
+// Urldecode decodes URL-encoded string, "+" is decoded as a space and invalid %XX are kept
+//
+// see http://php.net/manual/en/function.urldecode.php
+func Urldecode(str string) string {
+	return urlDecode(str, false)
+}
+
+// Rawurldecode decode URL-encoded strings, "+" is kept
+//
+// see http://php.net/manual/en/function.rawurldecode.php
+func Rawurldecode(str string) string {
+	return urlDecode(str, true)
+}
+
+func urlEncode(str string, raw bool) string {
+	const digits = "0123456789ABCDEF"
+	var b strings.Builder
+	b.Grow(len(str))
+	for i := 0; i < len(str); i++ {
+		c := str[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', raw && c == '~':
+			b.WriteByte(c)
+		case !raw && c == ' ':
+			b.WriteByte('+')
+		default:
+			b.WriteByte('%')
+			b.WriteByte(digits[c>>4])
+			b.WriteByte(digits[c&0xf])
+		}
+	}
+	return b.String()
+}
+
+func urlDecode(str string, raw bool) string {
+	res := make([]byte, 0, len(str))
+	for i := 0; i < len(str); i++ {
+		switch c := str[i]; {
+		case c == '+' && !raw:
+			res = append(res, ' ')
+		case c == '%' && i+2 < len(str) && isHexDigit(str[i+1]) && isHexDigit(str[i+2]):
+			v, _ := strconv.ParseUint(str[i+1:i+3], 16, 8)
+			res = append(res, byte(v))
+			i += 2
+		default:
+			res = append(res, c)
+		}
+	}
+	return string(res)
+}
+
+// ParseUrl parse a URL and return its components
+//
+// The same as PHP, the keys of the result are "scheme", "host", "port", "user", "pass", "path",
+// "query" and "fragment", only the ones in url are set. The port is an int and the others are
+// strings. An error is returned for seriously malformed URLs.
+// .eg ParseUrl("https://user@example.com:8080/a?b=1#c")
+//
+// see http://php.net/manual/en/function.parse-url.php
+func ParseUrl(url string) (map[string]interface{}, error) {
+	res := make(map[string]interface{})
+	if !parseURL(url, res) {
+		return nil, fmt.Errorf("malformed URL: %s", url)
+	}
+	return res, nil
+}
+
+// ParseUrlComponent returns a component of URL like ParseUrl with the PhpUrl* component,
+// it's nil if the component is not in url
+//
+// see http://php.net/manual/en/function.parse-url.php
+func ParseUrlComponent(url string, component int) (interface{}, error) {
+	if component < 0 || component >= len(urlComponents) {
+		return nil, fmt.Errorf("invalid URL component identifier %d", component)
+	}
+	res, err := ParseUrl(url)
+	if err != nil {
+		return nil, err
+	}
+	return res[urlComponents[component]], nil
+}
+
+// parseURL is the port of PHP's php_url_parse_ex2, it sets the components to res and returns false
+// if url is malformed
+func parseURL(str string, res map[string]interface{}) bool {
+	const (
+		parsePort = iota
+		parseHost
+		justPath
+	)
+	s, ue := 0, len(str)
+	set := func(key string, from, to int) {
+		res[key] = strings.Map(func(r rune) rune {
+			if r < 0x20 || r == 0x7f {
+				return '_'
+			}
+			return r
+		}, str[from:to])
+	}
+	isSlashes := func(i int) bool {
+		return i+1 < ue && str[i] == '/' && str[i+1] == '/'
+	}
+	isDigit := func(c byte) bool {
+		return '0' <= c && c <= '9'
+	}
+
+	// parse scheme
+	next := justPath
+	e := strings.IndexByte(str, ':')
+	switch {
+	case e > 0:
+		valid := true
+		for p := s; p < e; p++ {
+			c := str[p]
+			if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || isDigit(c) || c == '+' || c == '.' || c == '-') {
+				valid = false
+				break
+			}
+		}
+		if !valid {
+			if e+1 < ue && e < strcspn(str, s, ue, "?#") {
+				next = parsePort
+			} else if isSlashes(s) {
+				s += 2
+				next = parseHost
+			}
+			break
+		}
+		if e+1 == ue {
+			set("scheme", s, e)
+			return true
+		}
+		if str[e+1] != '/' {
+			// a.com:80 is a host and port, mailto: and the like are schemes without slashes
+			p := e + 1
+			for p < ue && isDigit(str[p]) {
+				p++
+			}
+			if (p == ue || str[p] == '/') && p-e < 7 {
+				next = parsePort
+				break
+			}
+			set("scheme", s, e)
+			s = e + 1
+			break
+		}
+		set("scheme", s, e)
+		if e+2 < ue && str[e+2] == '/' {
+			s = e + 3
+			next = parseHost
+			if strings.EqualFold(str[:e], "file") && e+3 < ue && str[e+3] == '/' {
+				// windows drive letters as in file:///c:/somedir/file.txt
+				if e+5 < ue && str[e+5] == ':' {
+					s = e + 4
+				}
+				next = justPath
+			}
+		} else {
+			s = e + 1
+		}
+	case e == 0:
+		next = parsePort
+	case isSlashes(s):
+		s += 2
+		next = parseHost
+	}
+
+	if next == parsePort {
+		p := e + 1
+		pp := p
+		for pp < ue && pp-p < 6 && isDigit(str[pp]) {
+			pp++
+		}
+		switch {
+		case pp-p > 0 && pp-p < 6 && (pp == ue || str[pp] == '/'):
+			port, _ := strconv.Atoi(str[p:pp])
+			if port > 65535 {
+				return false
+			}
+			res["port"] = port
+			if isSlashes(s) {
+				s += 2
+			}
+			next = parseHost
+		case p == pp && pp == ue:
+			return false
+		case isSlashes(s):
+			s += 2
+			next = parseHost
+		default:
+			next = justPath
+		}
+	}
+
+	if next == parseHost {
+		e = strcspn(str, s, ue, "/?#")
+
+		// check for login and password
+		if at := strings.LastIndexByte(str[s:e], '@'); at >= 0 {
+			p := s + at
+			if c := strings.IndexByte(str[s:p], ':'); c >= 0 {
+				set("user", s, s+c)
+				set("pass", s+c+1, p)
+			} else {
+				set("user", s, p)
+			}
+			s = p + 1
+		}
+
+		// check for port, but not in an IPv6 address like [::1]
+		p := -1
+		if !(s < ue && str[s] == '[' && str[e-1] == ']') {
+			if c := strings.LastIndexByte(str[s:e], ':'); c >= 0 {
+				p = s + c
+			}
+		}
+		if p >= 0 {
+			if _, ok := res["port"]; !ok {
+				if e-p-1 > 5 {
+					return false
+				} else if e-p-1 > 0 {
+					port, ok := strtol(str[p+1 : e])
+					if !ok || port < 0 || port > 65535 {
+						return false
+					}
+					res["port"] = port
+				}
+			}
+		} else {
+			p = e
+		}
+
+		if p-s < 1 {
+			return false
+		}
+		set("host", s, p)
+		if e == ue {
+			return true
+		}
+		s = e
+	}
+
+	// just path
+	e = ue
+	if p := strings.IndexByte(str[s:e], '#'); p >= 0 {
+		set("fragment", s+p+1, e)
+		e = s + p
+	}
+	if p := strings.IndexByte(str[s:e], '?'); p >= 0 {
+		set("query", s+p+1, e)
+		e = s + p
+	}
+	if s < e || s == ue {
+		set("path", s, e)
+	}
+	return true
+}
+
+// strcspn returns the index of the first byte of chars in str[s:e], or e if there is none
+func strcspn(str string, s, e int, chars string) int {
+	if i := strings.IndexAny(str[s:e], chars); i >= 0 {
+		return s + i
+	}
+	return e
+}
+
+// strtol parses the leading integer of str like C's strtol, false if there are no digits
+func strtol(str string) (int, bool) {
+	i := 0
+	for i < len(str) && isSpace(str[i]) {
+		i++
+	}
+	neg := false
+	if i < len(str) && (str[i] == '+' || str[i] == '-') {
+		neg = str[i] == '-'
+		i++
+	}
+	n, start := 0, i
+	for ; i < len(str) && '0' <= str[i] && str[i] <= '9' && n < 1<<31; i++ {
+		n = n*10 + int(str[i]-'0')
+	}
+	if i == start {
+		return 0, false
+	}
+	if neg {
+		n = -n
+	}
+	return n, true
+}
+
+// HttpBuildQuery generate URL-encoded query string
+//
+// data is a map, slice or array, and the values can be nested maps, slices and arrays which are
+// encoded like "a%5Bb%5D%5B0%5D=1" as PHP. Go maps have no order, so the keys of maps are sorted
+// like PHP's ksort. nil values are skipped, booleans are "1" and "0".
+// encType is PhpQueryRFC1738 or PhpQueryRFC3986, the optional params are numeric_prefix which is
+// prepended to the numeric keys of the top level, and arg_separator which is "&" by default.
+// .eg HttpBuildQuery(map[string]interface{}{"a": []int{1, 2}}, PhpQueryRFC1738) returns "a%5B0%5D=1&a%5B1%5D=2"
+//
+// see http://php.net/manual/en/function.http-build-query.php
+func HttpBuildQuery(data interface{}, encType int, params ...string) string {
+	var numericPrefix, argSeparator = "", "&"
+	if len(params) > 0 {
+		numericPrefix = params[0]
+	}
+	if len(params) > 1 {
+		argSeparator = params[1]
+	}
+	encode := Urlencode
+	if encType == PhpQueryRFC3986 {
+		encode = Rawurlencode
+	}
+
+	var b strings.Builder
+	var build func(v reflect.Value, prefix string)
+	build = func(v reflect.Value, prefix string) {
+		for _, entry := range queryEntries(v) {
+			key, val := entry.key, entry.value
+
+			var name string
+			switch {
+			case prefix != "":
+				name = prefix + "%5B" + encode(key) + "%5D"
+			case isIntKey(key):
+				name = numericPrefix + key
+			default:
+				name = encode(key)
+			}
+
+			for val.Kind() == reflect.Interface || val.Kind() == reflect.Ptr {
+				if val.IsNil() {
+					break
+				}
+				val = val.Elem()
+			}
+			switch val.Kind() {
+			case reflect.Invalid, reflect.Interface, reflect.Ptr, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+				continue
+			case reflect.Map, reflect.Slice, reflect.Array:
+				if val.Kind() == reflect.Slice && val.IsNil() {
+					continue
+				}
+				build(val, name)
+				continue
+			}
+
+			if b.Len() > 0 {
+				b.WriteString(argSeparator)
+			}
+			b.WriteString(name)
+			b.WriteByte('=')
+			switch val.Kind() {
+			case reflect.Bool:
+				if val.Bool() {
+					b.WriteByte('1')
+				} else {
+					b.WriteByte('0')
+				}
+			case reflect.Float32, reflect.Float64:
+				b.WriteString(encode(formatFloat(val.Float(), 14)))
+			default:
+				b.WriteString(encode(fmt.Sprint(val.Interface())))
+			}
+		}
+	}
+	build(reflect.ValueOf(data), "")
+	return b.String()
+}
+
+// queryEntry is a key and value of the data of HttpBuildQuery
+type queryEntry struct {
+	key   string
+	value reflect.Value
+}
+
+// queryEntries returns the keys and values of a map, slice or array, maps are sorted by the keys
+func queryEntries(v reflect.Value) []queryEntry {
+	var res []queryEntry
+	switch v.Kind() {
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			res = append(res, queryEntry{fmt.Sprint(k.Interface()), v.MapIndex(k)})
+		}
+		sort.Slice(res, func(i, j int) bool {
+			a, b := res[i].key, res[j].key
+			if isIntKey(a) && isIntKey(b) {
+				x, _ := strconv.ParseInt(a, 10, 64)
+				y, _ := strconv.ParseInt(b, 10, 64)
+				return x < y
+			}
+			return a < b
+		})
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			res = append(res, queryEntry{strconv.Itoa(i), v.Index(i)})
+		}
+	}
+	return res
+}
+
+// ParseStr parses the string as if it were the query string passed via a URL
+//
+// The same as PHP, "a[]=1&a[]=2&b[x][y]=3" is parsed as the nested maps
+// {"a": {"0": "1", "1": "2"}, "b": {"x": {"y": "3"}}}, the values are strings or
+// map[string]interface{}. Spaces and dots in the names are converted to underscores.
+//
+// see http://php.net/manual/en/function.parse-str.php
+func ParseStr(str string) map[string]interface{} {
+	res := make(map[string]interface{})
+	for _, pair := range strings.Split(str, "&") {
+		if pair == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(pair, "=")
+		registerVariable(res, Urldecode(name), Urldecode(value))
+	}
+	return res
+}
+
+// registerVariable is the port of PHP's php_register_variable_ex, it sets value to vars
+// by the name like "a[b][]"
+func registerVariable(vars map[string]interface{}, name, value string) {
+	const maxNestingLevel = 64
+
+	name = strings.TrimLeft(name, " ")
+	if i := strings.IndexByte(name, 0); i >= 0 {
+		name = name[:i]
+	}
+	b := []byte(name)
+
+	// ensure that we don't have spaces or dots in the variable name
+	isArray, p := false, 0
+	for ; p < len(b); p++ {
+		if b[p] == ' ' || b[p] == '.' {
+			b[p] = '_'
+		} else if b[p] == '[' {
+			isArray = true
+			break
+		}
+	}
+	if p == 0 {
+		return
+	}
+	base := string(b[:p])
+
+	sym, index, hasIndex := vars, base, true
+	for level := 1; isArray; level++ {
+		if level > maxNestingLevel {
+			delete(vars, base)
+			return
+		}
+
+		p++
+		start := p
+		if p < len(b) && b[p] == ' ' {
+			p++
+		}
+		newIndex, newHasIndex := "", false
+		if p >= len(b) || b[p] != ']' {
+			end := strings.IndexByte(string(b[p:]), ']')
+			if end < 0 {
+				// not an index, "a[b" is the variable "a_b"
+				b[start-1] = '_'
+				for i := start; i < len(b); i++ {
+					if b[i] == ' ' || b[i] == '.' || b[i] == '[' {
+						b[i] = '_'
+					}
+				}
+				if level == 1 {
+					index = string(b)
+				}
+				break
+			}
+			p += end
+			newIndex, newHasIndex = string(b[start:p]), true
+		}
+
+		var child map[string]interface{}
+		if !hasIndex {
+			child = make(map[string]interface{})
+			sym[nextIndex(sym)] = child
+		} else if m, ok := sym[index].(map[string]interface{}); ok {
+			child = m
+		} else {
+			child = make(map[string]interface{})
+			sym[index] = child
+		}
+		sym, index, hasIndex = child, newIndex, newHasIndex
+
+		p++
+		isArray = p < len(b) && b[p] == '['
+	}
+
+	if !hasIndex {
+		index = nextIndex(sym)
+	}
+	sym[index] = value
+}
+
+// nextIndex returns the key of "a[]" like PHP, the biggest integer key plus one
+func nextIndex(m map[string]interface{}) string {
+	next, found := int64(0), false
+	for k := range m {
+		if !isIntKey(k) {
+			continue
+		}
+		if n, _ := strconv.ParseInt(k, 10, 64); !found || n+1 > next {
+			next, found = n+1, true
+		}
+	}
+	return strconv.FormatInt(next, 10)
+}
+
+// isIntKey checks if key is an integer key of PHP arrays like "12" and "-3", but not "012" or "1.5"
+func isIntKey(key string) bool {
+	n, err := strconv.ParseInt(key, 10, 64)
+	return err == nil && strconv.FormatInt(n, 10) == key
+}
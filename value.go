@@ -0,0 +1,1109 @@
+package php
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Kind is the type of a Value
+type Kind int
+
+// kinds of Value
+const (
+	KindNull Kind = iota
+	KindBool
+	KindInt
+	KindFloat
+	KindString
+	KindArray
+)
+
+// String returns the name of the kind like PHP's get_debug_type, .eg "null", "int" and "array"
+func (k Kind) String() string {
+	switch k {
+	case KindNull:
+		return "null"
+	case KindBool:
+		return "bool"
+	case KindInt:
+		return "int"
+	case KindFloat:
+		return "float"
+	case KindString:
+		return "string"
+	case KindArray:
+		return "array"
+	}
+	return "unknown"
+}
+
+// Value is a dynamic value like PHP's zval, it holds a null, bool, int, float, string or an ordered array
+//
+// The zero Value is null. The keys of arrays are ints or strings normalized like PHP, so the key "5"
+// is the int 5 while "05" and "5.0" stay strings, and the entries keep their insertion order.
+// Arrays are copied on write like PHP: NewValue, Set, Get and ToArray share the entries of an array,
+// and a Value copies shared entries before it changes them, so after b := a; b.Set(5, 2) or
+// c := NewValue(a); c.Set(5, 2), a is unchanged. The Value which made the last change of its entries
+// keeps changing them in place, use b := a.Clone() when a changes later while b should not.
+type Value struct {
+	kind Kind
+	b    bool
+	i    int
+	f    float64
+	s    string
+	a    *valueArray
+}
+
+// valueArray holds the entries of an array Value, keys are int or string
+//
+// next is the key of the next appended element, which is one more than the greatest int key ever
+// set even if it's negative like PHP 8.3, or math.MinInt if there has been no int key and it's 0.
+// owner is the only Value which may change the entries in place, nil once they are shared.
+type valueArray struct {
+	keys   []interface{}
+	values map[interface{}]Value
+	next   int
+	owner  *Value
+}
+
+// NewValue convert a Go value to Value
+//
+// Integers, floats, strings, []byte and bools keep their kind, unsigned integers greater than
+// math.MaxInt64 become floats like PHP. Slices and Go arrays become lists, maps become arrays sorted
+// by key with the int keys first, and structs become arrays of their exported fields, named by their
+// json tag if any, the map keys which can't be array keys are skipped. A struct, or a pointer to a
+// struct, which implements fmt.Stringer becomes the string it returns, so a time.Time is its String.
+// Pointers are followed, a pointer, map or slice which refers back to one of the values containing
+// it is null instead of recursing forever, nil and other types are null.
+// .eg NewValue([]string{"a", "b"}) returns the array [0 => "a", 1 => "b"],
+// NewValue(map[string]int{"b": 2, "a": 1}) returns the array ["a" => 1, "b" => 2]
+func NewValue(value interface{}) Value {
+	return newValue(value, nil)
+}
+
+// visit is a pointer, map or slice NewValue is converting, len tells apart the slices of an array
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+// newValue is NewValue, path holds the pointers, maps and slices being converted to detect cycles
+func newValue(value interface{}, path map[visit]bool) Value {
+	switch x := value.(type) {
+	case nil:
+		return Value{}
+	case Value:
+		return x.share()
+	case *Value:
+		if x == nil {
+			return Value{}
+		}
+		return x.share()
+	case bool:
+		return Value{kind: KindBool, b: x}
+	case int:
+		return Value{kind: KindInt, i: x}
+	case float64:
+		return Value{kind: KindFloat, f: x}
+	case string:
+		return Value{kind: KindString, s: x}
+	case []byte:
+		return Value{kind: KindString, s: string(x)}
+	}
+
+	rv := reflect.ValueOf(value)
+	if s, ok := value.(fmt.Stringer); ok && (rv.Kind() == reflect.Struct ||
+		rv.Kind() == reflect.Ptr && !rv.IsNil() && rv.Elem().Kind() == reflect.Struct) {
+		return Value{kind: KindString, s: s.String()}
+	}
+	if k := rv.Kind(); (k == reflect.Ptr || k == reflect.Map || k == reflect.Slice) && !rv.IsNil() {
+		key := visit{ptr: rv.Pointer(), typ: rv.Type()}
+		if k == reflect.Slice {
+			key.len = rv.Len()
+		}
+		if path[key] {
+			return Value{}
+		}
+		if path == nil {
+			path = make(map[visit]bool)
+		}
+		path[key] = true
+		defer delete(path, key)
+	}
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		return Value{kind: KindBool, b: rv.Bool()}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return Value{kind: KindInt, i: int(rv.Int())}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if rv.Uint() > math.MaxInt64 {
+			return Value{kind: KindFloat, f: float64(rv.Uint())}
+		}
+		return Value{kind: KindInt, i: int(rv.Uint())}
+	case reflect.Float32, reflect.Float64:
+		return Value{kind: KindFloat, f: rv.Float()}
+	case reflect.String:
+		return Value{kind: KindString, s: rv.String()}
+	case reflect.Slice, reflect.Array:
+		arr := NewArray()
+		for i := 0; i < rv.Len(); i++ {
+			arr.Append(newValue(rv.Index(i).Interface(), path))
+		}
+		return arr
+	case reflect.Map:
+		keys := make([]Value, 0, rv.Len())
+		values := make(map[interface{}]Value, rv.Len())
+		for _, k := range rv.MapKeys() {
+			key, err := arrayKey(k.Interface())
+			if err != nil {
+				continue
+			}
+			keys = append(keys, NewValue(key))
+			values[key] = newValue(rv.MapIndex(k).Interface(), path)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i].kind != keys[j].kind {
+				return keys[i].kind == KindInt
+			}
+			if keys[i].kind == KindInt {
+				return keys[i].i < keys[j].i
+			}
+			return keys[i].s < keys[j].s
+		})
+		arr := NewArray()
+		for _, k := range keys {
+			arr.Set(k, values[k.Interface()])
+		}
+		return arr
+	case reflect.Struct:
+		arr := NewArray()
+		t := rv.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			name := field.Name
+			if tag, _, _ := strings.Cut(field.Tag.Get("json"), ","); tag == "-" {
+				continue
+			} else if tag != "" {
+				name = tag
+			}
+			arr.Set(name, newValue(rv.Field(i).Interface(), path))
+		}
+		return arr
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return Value{}
+		}
+		return newValue(rv.Elem().Interface(), path)
+	}
+	return Value{}
+}
+
+// NewArray create an array Value which is a list of values
+// .eg NewArray() returns an empty array, NewArray("a", 1) returns the array [0 => "a", 1 => 1]
+func NewArray(values ...interface{}) Value {
+	arr := Value{kind: KindArray, a: &valueArray{values: make(map[interface{}]Value, len(values)), next: math.MinInt}}
+	for _, value := range values {
+		arr.Append(value)
+	}
+	return arr
+}
+
+// Kind returns the kind of v
+func (v Value) Kind() Kind {
+	return v.kind
+}
+
+// IsNull reports if v is null
+func (v Value) IsNull() bool {
+	return v.kind == KindNull
+}
+
+// Bool converts v to bool like PHP's (bool) cast, see Boolval
+func (v Value) Bool() bool {
+	switch v.kind {
+	case KindBool:
+		return v.b
+	case KindInt:
+		return v.i != 0
+	case KindFloat:
+		return v.f != 0
+	case KindString:
+		return v.s != "" && v.s != "0"
+	case KindArray:
+		return len(v.a.keys) > 0
+	}
+	return false
+}
+
+// Int converts v to int like PHP's (int) cast, see Intval, arrays are 1 if they are not empty
+func (v Value) Int() int {
+	switch v.kind {
+	case KindBool, KindArray:
+		if v.Bool() {
+			return 1
+		}
+		return 0
+	case KindInt:
+		return v.i
+	case KindFloat:
+		return floatToInt(v.f)
+	case KindString:
+		return toInt(v.s)
+	}
+	return 0
+}
+
+// Float converts v to float64 like PHP's (float) cast, see Floatval
+func (v Value) Float() float64 {
+	switch v.kind {
+	case KindFloat:
+		return v.f
+	case KindString:
+		return toFloat(v.s)
+	}
+	return float64(v.Int())
+}
+
+// String converts v to string like PHP's (string) cast, see Strval, arrays are "Array"
+func (v Value) String() string {
+	switch v.kind {
+	case KindBool:
+		if v.b {
+			return "1"
+		}
+		return ""
+	case KindInt:
+		return strconv.Itoa(v.i)
+	case KindFloat:
+		return formatFloat(v.f, 14)
+	case KindString:
+		return v.s
+	case KindArray:
+		return "Array"
+	}
+	return ""
+}
+
+// ToArray converts v to array like PHP's (array) cast, null is an empty array and the other
+// scalars are an array of one element
+func (v Value) ToArray() Value {
+	switch v.kind {
+	case KindArray:
+		return v.share()
+	case KindNull:
+		return NewArray()
+	}
+	return NewArray(v)
+}
+
+// Interface returns v as a Go value, which is nil, bool, int, float64 or string, lists are
+// []interface{} and the other arrays are map[interface{}]interface{} with int and string keys
+func (v Value) Interface() interface{} {
+	switch v.kind {
+	case KindBool:
+		return v.b
+	case KindInt:
+		return v.i
+	case KindFloat:
+		return v.f
+	case KindString:
+		return v.s
+	case KindArray:
+		if v.IsList() {
+			list := make([]interface{}, len(v.a.keys))
+			for i, k := range v.a.keys {
+				list[i] = v.a.values[k].Interface()
+			}
+			return list
+		}
+		m := make(map[interface{}]interface{}, len(v.a.keys))
+		for _, k := range v.a.keys {
+			m[k] = v.a.values[k].Interface()
+		}
+		return m
+	}
+	return nil
+}
+
+// IsList reports if v is an array whose keys are 0, 1, 2... in order
+//
+// see http://php.net/manual/en/function.array-is-list.php
+func (v Value) IsList() bool {
+	if v.kind != KindArray {
+		return false
+	}
+	for i, k := range v.a.keys {
+		if k != i {
+			return false
+		}
+	}
+	return true
+}
+
+// Len returns the number of elements of an array, 0 for the other kinds
+func (v Value) Len() int {
+	if v.kind != KindArray {
+		return 0
+	}
+	return len(v.a.keys)
+}
+
+// Has reports if the array v has key
+func (v Value) Has(key interface{}) bool {
+	if v.kind != KindArray {
+		return false
+	}
+	k, err := arrayKey(key)
+	if err != nil {
+		return false
+	}
+	_, ok := v.a.values[k]
+	return ok
+}
+
+// Get returns the element of the array v at key, null if there is none
+// .eg NewValue(map[string]int{"5": 1}).Get(5) returns 1
+func (v Value) Get(key interface{}) Value {
+	if v.kind != KindArray {
+		return Value{}
+	}
+	k, err := arrayKey(key)
+	if err != nil {
+		return Value{}
+	}
+	return v.a.values[k]
+}
+
+// Set set the element of the array v at key like $v[key] = value, a null v becomes an array
+//
+// It fails and v is unchanged if v is another scalar or key is an array, which PHP rejects too.
+func (v *Value) Set(key, value interface{}) error {
+	k, err := arrayKey(key)
+	if err != nil {
+		return err
+	}
+	if err := v.autoArray(); err != nil {
+		return err
+	}
+	v.own()
+	if _, ok := v.a.values[k]; !ok {
+		v.a.keys = append(v.a.keys, k)
+		if n, ok := k.(int); ok && n >= v.a.next {
+			if n < math.MaxInt {
+				v.a.next = n + 1
+			} else {
+				v.a.next = n
+			}
+		}
+	}
+	v.a.values[k] = NewValue(value).share()
+	return nil
+}
+
+// Append append value to the array v like $v[] = value, a null v becomes an array
+//
+// The key is one more than the greatest int key like PHP 8.3, so it's -4 after the key -5, and 0 if
+// there is no int key. It fails if v is another scalar or the key math.MaxInt is already used.
+// .eg appending to [-5 => "a"] returns [-5 => "a", -4 => "b"]
+func (v *Value) Append(value interface{}) error {
+	if err := v.autoArray(); err != nil {
+		return err
+	}
+	next := v.a.next
+	if next == math.MinInt {
+		next = 0
+	}
+	if _, ok := v.a.values[next]; ok {
+		return errors.New("cannot add element to the array as the next element is already occupied")
+	}
+	return v.Set(next, value)
+}
+
+// Delete remove the element of the array v at key like unset($v[key]), nothing happens if v is not
+// an array, and it fails if key is an array
+func (v *Value) Delete(key interface{}) error {
+	k, err := arrayKey(key)
+	if err != nil || v.kind != KindArray {
+		return err
+	}
+	if _, ok := v.a.values[k]; !ok {
+		return nil
+	}
+	v.own()
+	delete(v.a.values, k)
+	for i, key := range v.a.keys {
+		if key == k {
+			v.a.keys = append(v.a.keys[:i], v.a.keys[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// Keys returns the keys of the array v in order
+func (v Value) Keys() []Value {
+	if v.kind != KindArray {
+		return nil
+	}
+	keys := make([]Value, len(v.a.keys))
+	for i, k := range v.a.keys {
+		keys[i] = NewValue(k)
+	}
+	return keys
+}
+
+// Values returns the elements of the array v in order
+func (v Value) Values() []Value {
+	if v.kind != KindArray {
+		return nil
+	}
+	values := make([]Value, len(v.a.keys))
+	for i, k := range v.a.keys {
+		values[i] = v.a.values[k]
+	}
+	return values
+}
+
+// Range call f for each element of the array v in order until f returns false
+func (v Value) Range(f func(key, value Value) bool) {
+	if v.kind != KindArray {
+		return
+	}
+	for _, k := range append([]interface{}(nil), v.a.keys...) {
+		if value, ok := v.a.values[k]; ok && !f(NewValue(k), value) {
+			return
+		}
+	}
+}
+
+// Clone returns a deep copy of v, so the arrays of the copy don't share their entries with v
+func (v Value) Clone() Value {
+	if v.kind != KindArray {
+		return v
+	}
+	arr := NewArray()
+	arr.a.next = v.a.next
+	arr.a.keys = append(arr.a.keys, v.a.keys...)
+	for k, value := range v.a.values {
+		arr.a.values[k] = value.Clone()
+	}
+	return arr
+}
+
+// own makes v the owner of its array before a change, copying the entries if they may be shared
+func (v *Value) own() {
+	if v.a.owner == v {
+		return
+	}
+	a := &valueArray{
+		keys:   append(make([]interface{}, 0, len(v.a.keys)+1), v.a.keys...),
+		values: make(map[interface{}]Value, len(v.a.values)+1),
+		next:   v.a.next,
+		owner:  v,
+	}
+	for k, value := range v.a.values {
+		a.values[k] = value
+	}
+	v.a = a
+}
+
+// share marks the entries of the array v as shared, so every Value holding them copies them before
+// its next change
+func (v Value) share() Value {
+	if v.kind == KindArray {
+		v.a.owner = nil
+	}
+	return v
+}
+
+// autoArray turns a null v into an empty array like PHP does on $v[] = 1, it fails for the other scalars
+func (v *Value) autoArray() error {
+	switch v.kind {
+	case KindArray:
+	case KindNull:
+		*v = NewArray()
+	default:
+		return fmt.Errorf("cannot use a scalar value of type %s as an array", v.kind)
+	}
+	return nil
+}
+
+// key returns v as an array key, which is int or string, arrays are illegal keys
+func (v Value) key() (interface{}, error) {
+	switch v.kind {
+	case KindNull:
+		return "", nil
+	case KindBool, KindInt:
+		return v.Int(), nil
+	case KindFloat:
+		return floatToInt(v.f), nil
+	case KindString:
+		if isIntKey(v.s) {
+			n, _ := strconv.Atoi(v.s)
+			return n, nil
+		}
+		return v.s, nil
+	}
+	return nil, errors.New("illegal offset type")
+}
+
+// arrayKey normalizes key like the keys of PHP arrays
+func arrayKey(key interface{}) (interface{}, error) {
+	switch x := key.(type) {
+	case int:
+		return x, nil
+	case string:
+		if isIntKey(x) {
+			n, _ := strconv.Atoi(x)
+			return n, nil
+		}
+		return x, nil
+	}
+	return NewValue(key).key()
+}
+
+// Compare compare v with w like PHP 8's spaceship operator v <=> w, it returns -1, 0 or 1
+//
+// Numbers and numeric strings are compared as numbers, a number and a non-numeric string are
+// compared as strings and null or bool with anything else as bools. Arrays with fewer elements are
+// smaller, arrays of the same size are compared element by element and an array is greater than
+// anything else. NaN and arrays whose keys differ are uncomparable and return 1.
+// .eg NewValue("abc").Compare(NewValue(0)) returns 1, NewValue("1e1").Compare(NewValue("10")) returns 0
+//
+// see http://php.net/manual/en/language.operators.comparison.php
+func (v Value) Compare(w Value) int {
+	switch {
+	case v.kind == KindArray && w.kind == KindArray:
+		return compareArrays(v, w)
+	case v.kind == KindString && w.kind == KindString:
+		return compareStrings(v.s, w.s)
+	case v.kind == KindNull && w.kind == KindString:
+		if w.s == "" {
+			return 0
+		}
+		return -1
+	case v.kind == KindString && w.kind == KindNull:
+		if v.s == "" {
+			return 0
+		}
+		return 1
+	case v.kind == KindNull || v.kind == KindBool || w.kind == KindNull || w.kind == KindBool:
+		return compareBools(v.Bool(), w.Bool())
+	case v.kind == KindArray:
+		return 1
+	case w.kind == KindArray:
+		return -1
+	case v.kind == KindString:
+		return -compareNumberString(w, v.s)
+	case w.kind == KindString:
+		return compareNumberString(v, w.s)
+	}
+	return compareNumbers(v, w)
+}
+
+// Equal reports if v == w with PHP 8's loose comparison
+// .eg NewValue("1e3").Equal(NewValue(1000)) returns true, NewValue("abc").Equal(NewValue(0)) returns false
+func (v Value) Equal(w Value) bool {
+	return v.Compare(w) == 0
+}
+
+// Identical reports if v === w, which means they have the same kind and value, arrays must have
+// the same keys in the same order and identical elements
+func (v Value) Identical(w Value) bool {
+	if v.kind != w.kind {
+		return false
+	}
+	switch v.kind {
+	case KindBool:
+		return v.b == w.b
+	case KindInt:
+		return v.i == w.i
+	case KindFloat:
+		return v.f == w.f
+	case KindString:
+		return v.s == w.s
+	case KindArray:
+		if len(v.a.keys) != len(w.a.keys) {
+			return false
+		}
+		for i, k := range v.a.keys {
+			if w.a.keys[i] != k || !v.a.values[k].Identical(w.a.values[k]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// compareArrays compares two arrays like PHP's zend_compare_arrays
+func compareArrays(v, w Value) int {
+	if len(v.a.keys) != len(w.a.keys) {
+		return compareNumbers(NewValue(len(v.a.keys)), NewValue(len(w.a.keys)))
+	}
+	for _, k := range v.a.keys {
+		wv, ok := w.a.values[k]
+		if !ok {
+			return 1
+		}
+		if c := v.a.values[k].Compare(wv); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// compareStrings compares two strings like PHP's zendi_smart_strcmp, numerically if both are numeric
+func compareStrings(s1, s2 string) int {
+	n1, ok1 := numericValue(s1)
+	n2, ok2 := numericValue(s2)
+	if !ok1 || !ok2 {
+		return strings.Compare(s1, s2)
+	}
+	o1, o2 := intOverflow(s1, n1), intOverflow(s2, n2)
+	switch {
+	// two integers which overflowed to the same float can only be compared as strings
+	case o1 != 0 && o1 == o2 && n1.f == n2.f:
+		return strings.Compare(s1, s2)
+	case n1.kind == KindInt && o2 != 0:
+		return -o2
+	case n2.kind == KindInt && o1 != 0:
+		return o1
+	// so can two numbers which overflowed to the same infinity
+	case n1.kind == KindFloat && n2.kind == KindFloat && n1.f == n2.f && math.IsInf(n1.f, 0):
+		return strings.Compare(s1, s2)
+	}
+	return compareNumbers(n1, n2)
+}
+
+// intOverflow returns 1 or -1 if the numeric string s is an integer above or below int and was
+// parsed to the float n, like the oflow of PHP's is_numeric_string_ex, 0 otherwise
+func intOverflow(s string, n Value) int {
+	if n.kind != KindFloat {
+		return 0
+	}
+	if _, isFloat := numericPrefix(s); isFloat {
+		return 0
+	}
+	if n.f < 0 {
+		return -1
+	}
+	return 1
+}
+
+// compareNumberString compares the int or float n with s, as strings if s is not numeric
+func compareNumberString(n Value, s string) int {
+	if num, ok := numericValue(s); ok {
+		return compareNumbers(n, num)
+	}
+	return strings.Compare(n.String(), s)
+}
+
+// compareNumbers compares two ints or floats, NaN is greater than anything
+func compareNumbers(v, w Value) int {
+	if v.kind == KindInt && w.kind == KindInt {
+		switch {
+		case v.i < w.i:
+			return -1
+		case v.i > w.i:
+			return 1
+		}
+		return 0
+	}
+	f1, f2 := v.Float(), w.Float()
+	switch {
+	case f1 == f2:
+		return 0
+	case f1 < f2:
+		return -1
+	}
+	return 1
+}
+
+// compareBools compares two bools, false is less than true
+func compareBools(b1, b2 bool) int {
+	switch {
+	case b1 == b2:
+		return 0
+	case b2:
+		return -1
+	}
+	return 1
+}
+
+// numericValue returns the int or float value of s if it's a numeric string, see IsNumeric
+func numericValue(s string) (Value, bool) {
+	if !IsNumeric(s) {
+		return Value{}, false
+	}
+	n, isFloat, f := parseNumeric(s)
+	if isFloat {
+		return Value{kind: KindFloat, f: f}, true
+	}
+	return Value{kind: KindInt, i: n}, true
+}
+
+// MarshalJSON implements json.Marshaler like PHP's json_encode with the default flags
+//
+// Lists are encoded as JSON arrays and the other arrays as objects, "/" and the non-ASCII characters
+// are escaped, e.g. "\/" and "\u00e9". Invalid UTF-8, Inf and NaN can't be encoded.
+//
+// see http://php.net/manual/en/function.json-encode.php
+func (v Value) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	if err := appendJSON(&b, v); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// appendJSON writes v to b as JSON
+func appendJSON(b *bytes.Buffer, v Value) error {
+	switch v.kind {
+	case KindNull:
+		b.WriteString("null")
+	case KindBool:
+		b.WriteString(strconv.FormatBool(v.b))
+	case KindInt:
+		b.WriteString(strconv.Itoa(v.i))
+	case KindFloat:
+		if math.IsNaN(v.f) || math.IsInf(v.f, 0) {
+			return errors.New("inf and NaN cannot be JSON encoded")
+		}
+		b.WriteString(strings.Replace(formatFloat(v.f, -1), "E", "e", 1))
+	case KindString:
+		return appendJSONString(b, v.s)
+	case KindArray:
+		if v.IsList() {
+			b.WriteByte('[')
+			for i, k := range v.a.keys {
+				if i > 0 {
+					b.WriteByte(',')
+				}
+				if err := appendJSON(b, v.a.values[k]); err != nil {
+					return err
+				}
+			}
+			b.WriteByte(']')
+			return nil
+		}
+		b.WriteByte('{')
+		for i, k := range v.a.keys {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if err := appendJSONString(b, NewValue(k).String()); err != nil {
+				return err
+			}
+			b.WriteByte(':')
+			if err := appendJSON(b, v.a.values[k]); err != nil {
+				return err
+			}
+		}
+		b.WriteByte('}')
+	}
+	return nil
+}
+
+// appendJSONString writes s to b as a JSON string escaped like PHP
+func appendJSONString(b *bytes.Buffer, s string) error {
+	if !utf8.ValidString(s) {
+		return errors.New("malformed UTF-8 characters, possibly incorrectly encoded")
+	}
+	b.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\' || r == '/':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\b':
+			b.WriteString(`\b`)
+		case r == '\f':
+			b.WriteString(`\f`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r < 0x20 || r >= 0x80 && r < 0x10000:
+			fmt.Fprintf(b, `\u%04x`, r)
+		case r >= 0x10000:
+			r -= 0x10000
+			fmt.Fprintf(b, `\u%04x\u%04x`, 0xD800+r>>10, 0xDC00+r&0x3FF)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler like PHP's json_decode with associative arrays
+//
+// JSON arrays and objects become arrays keeping the order of their members, integers which
+// overflow int become floats.
+//
+// see http://php.net/manual/en/function.json-decode.php
+func (v *Value) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	value, err := decodeJSON(dec)
+	if err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("syntax error")
+	}
+	*v = value
+	return nil
+}
+
+// decodeJSON reads the next JSON value of dec
+func decodeJSON(dec *json.Decoder) (Value, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return Value{}, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		arr := NewArray()
+		for dec.More() {
+			var key interface{}
+			if t == '{' {
+				if key, err = dec.Token(); err != nil {
+					return Value{}, err
+				}
+			}
+			value, err := decodeJSON(dec)
+			if err != nil {
+				return Value{}, err
+			}
+			if key == nil {
+				err = arr.Append(value)
+			} else {
+				err = arr.Set(key, value)
+			}
+			if err != nil {
+				return Value{}, err
+			}
+		}
+		if _, err := dec.Token(); err != nil {
+			return Value{}, err
+		}
+		return arr, nil
+	case json.Number:
+		if n, err := strconv.Atoi(string(t)); err == nil {
+			return NewValue(n), nil
+		}
+		f, err := strconv.ParseFloat(string(t), 64)
+		if err != nil && !errors.Is(err, strconv.ErrRange) {
+			return Value{}, err
+		}
+		return NewValue(f), nil
+	}
+	return NewValue(tok), nil
+}
+
+// Serialize generates a storable representation of a value like PHP
+//
+// value is converted with NewValue, floats use the shortest representation like PHP's
+// serialize_precision -1.
+// .eg Serialize(map[string]interface{}{"a": 1, "b": []string{"x"}}) returns `a:2:{s:1:"a";i:1;s:1:"b";a:1:{i:0;s:1:"x";}}`
+//
+// see http://php.net/manual/en/function.serialize.php
+func Serialize(value interface{}) string {
+	var b strings.Builder
+	appendSerialize(&b, NewValue(value))
+	return b.String()
+}
+
+// appendSerialize writes v to b in the format of PHP's serialize
+func appendSerialize(b *strings.Builder, v Value) {
+	switch v.kind {
+	case KindNull:
+		b.WriteString("N;")
+	case KindBool:
+		b.WriteString("b:" + strconv.Itoa(v.Int()) + ";")
+	case KindInt:
+		b.WriteString("i:" + strconv.Itoa(v.i) + ";")
+	case KindFloat:
+		b.WriteString("d:" + formatFloat(v.f, -1) + ";")
+	case KindString:
+		b.WriteString("s:" + strconv.Itoa(len(v.s)) + ":\"" + v.s + "\";")
+	case KindArray:
+		b.WriteString("a:" + strconv.Itoa(len(v.a.keys)) + ":{")
+		for _, k := range v.a.keys {
+			appendSerialize(b, NewValue(k))
+			appendSerialize(b, v.a.values[k])
+		}
+		b.WriteByte('}')
+	}
+}
+
+// Unserialize creates a Value from a stored representation made by Serialize or PHP's serialize
+//
+// Objects and references are not supported. Like PHP, the data after the value is ignored.
+// .eg Unserialize(`a:1:{s:1:"a";d:0.5;}`) returns the array ["a" => 0.5]
+//
+// see http://php.net/manual/en/function.unserialize.php
+func Unserialize(str string) (Value, error) {
+	p := &unserializer{str: str}
+	v, ok := p.value(0)
+	if !ok {
+		return Value{}, fmt.Errorf("error at offset %d of %d bytes", p.pos, len(str))
+	}
+	return v, nil
+}
+
+// unserializer is the state of Unserialize, pos is the offset of the next byte to read
+type unserializer struct {
+	str string
+	pos int
+}
+
+// maxUnserializeDepth limits the nesting of arrays like PHP's unserialize_max_depth
+const maxUnserializeDepth = 4096
+
+// value reads the next value
+func (p *unserializer) value(depth int) (Value, bool) {
+	if p.pos+1 >= len(p.str) || depth > maxUnserializeDepth {
+		return Value{}, false
+	}
+	kind := p.str[p.pos]
+	if kind == 'N' {
+		if p.str[p.pos+1] != ';' {
+			return Value{}, false
+		}
+		p.pos += 2
+		return Value{}, true
+	}
+	if p.str[p.pos+1] != ':' {
+		return Value{}, false
+	}
+	start := p.pos
+	p.pos += 2
+	switch kind {
+	case 'b':
+		if s, ok := p.until(';'); ok && (s == "0" || s == "1") {
+			return NewValue(s == "1"), true
+		}
+	case 'i':
+		if s, ok := p.until(';'); ok {
+			if n, err := strconv.Atoi(s); err == nil {
+				return NewValue(n), true
+			}
+		}
+	case 'd':
+		if s, ok := p.until(';'); ok {
+			switch s {
+			case "INF":
+				return NewValue(math.Inf(1)), true
+			case "-INF":
+				return NewValue(math.Inf(-1)), true
+			case "NAN":
+				return NewValue(math.NaN()), true
+			}
+			if f, err := strconv.ParseFloat(s, 64); err == nil {
+				return NewValue(f), true
+			}
+		}
+	case 's':
+		if n, ok := p.length(); ok && p.pos+n+3 <= len(p.str) && p.str[p.pos] == '"' &&
+			p.str[p.pos+n+1:p.pos+n+3] == "\";" {
+			s := p.str[p.pos+1 : p.pos+n+1]
+			p.pos += n + 3
+			return NewValue(s), true
+		}
+	case 'a':
+		n, ok := p.length()
+		if !ok || p.pos >= len(p.str) || p.str[p.pos] != '{' {
+			break
+		}
+		p.pos++
+		arr := NewArray()
+		for i := 0; i < n; i++ {
+			if p.pos >= len(p.str) || p.str[p.pos] != 'i' && p.str[p.pos] != 's' {
+				return Value{}, false
+			}
+			key, ok := p.value(depth + 1)
+			if !ok {
+				return Value{}, false
+			}
+			value, ok := p.value(depth + 1)
+			if !ok || arr.Set(key, value) != nil {
+				return Value{}, false
+			}
+		}
+		if p.pos < len(p.str) && p.str[p.pos] == '}' {
+			p.pos++
+			return arr, true
+		}
+		return Value{}, false
+	}
+	p.pos = start
+	return Value{}, false
+}
+
+// until reads the bytes up to the terminator c and skips it
+func (p *unserializer) until(c byte) (string, bool) {
+	i := strings.IndexByte(p.str[p.pos:], c)
+	if i < 0 {
+		return "", false
+	}
+	s := p.str[p.pos : p.pos+i]
+	p.pos += i + 1
+	return s, true
+}
+
+// length reads the non-negative length of a string or an array followed by ":"
+func (p *unserializer) length() (int, bool) {
+	s, ok := p.until(':')
+	if !ok || s == "" || s[0] == '-' || s[0] == '+' {
+		return 0, false
+	}
+	n, err := strconv.Atoi(s)
+	return n, err == nil
+}
+
+// Scan implements sql.Scanner, so a Value can be the destination of a column of any type,
+// []byte becomes a string and time.Time is formatted as "2006-01-02 15:04:05"
+func (v *Value) Scan(src interface{}) error {
+	switch x := src.(type) {
+	case nil, bool, int64, float64, string:
+		*v = NewValue(x)
+	case []byte:
+		*v = NewValue(string(x))
+	case time.Time:
+		*v = NewValue(x.Format("2006-01-02 15:04:05"))
+	default:
+		return fmt.Errorf("unsupported type %T for Value", src)
+	}
+	return nil
+}
+
+// Value implements driver.Valuer, so a Value can be used as a query parameter, arrays can't
+func (v Value) Value() (driver.Value, error) {
+	switch v.kind {
+	case KindInt:
+		return int64(v.i), nil
+	case KindArray:
+		return nil, errors.New("array can not be used as a SQL value")
+	}
+	return v.Interface(), nil
+}
@@ -0,0 +1,648 @@
+package php
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValueSetScalar(t *testing.T) {
+	v := NewValue("str")
+	if err := v.Set("a", 1); err == nil {
+		t.Error("Set on a string expected an error")
+	}
+	if err := v.Append(1); err == nil {
+		t.Error("Append on a string expected an error")
+	}
+	if v.Kind() != KindString || v.String() != "str" {
+		t.Errorf("Set changed the scalar to %v", v)
+	}
+
+	var null Value
+	if err := null.Set("a", 1); err != nil || null.Get("a").Int() != 1 {
+		t.Errorf("Set on null = %v, %v", null, err)
+	}
+}
+
+func TestValueIllegalKey(t *testing.T) {
+	arr := NewArray()
+	if err := arr.Set(NewArray(), 1); err == nil {
+		t.Error("Set with an array key expected an error")
+	}
+	if err := arr.Set([]int{1}, 1); err == nil {
+		t.Error("Set with a slice key expected an error")
+	}
+	if arr.Has(NewArray()) || !arr.Get(NewArray()).IsNull() {
+		t.Error("Has/Get with an array key should find nothing")
+	}
+	if arr.Len() != 0 {
+		t.Errorf("Len = %d, want 0", arr.Len())
+	}
+}
+
+func TestValueAppendKey(t *testing.T) {
+	keys := func(v Value) []interface{} {
+		var res []interface{}
+		for _, k := range v.Keys() {
+			res = append(res, k.Interface())
+		}
+		return res
+	}
+	tests := []struct {
+		key  int
+		want []interface{}
+	}{
+		{-5, []interface{}{-5, -4}},
+		{0, []interface{}{0, 1}},
+		{3, []interface{}{3, 4}},
+	}
+	for _, test := range tests {
+		arr := NewArray()
+		arr.Set(test.key, "a")
+		if err := arr.Append("b"); err != nil {
+			t.Errorf("Append after %d error: %v", test.key, err)
+			continue
+		}
+		if got := keys(arr); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Append after %d keys = %v, want %v", test.key, got, test.want)
+		}
+	}
+
+	arr := NewArray()
+	arr.Append("a")
+	if got := keys(arr); !reflect.DeepEqual(got, []interface{}{0}) {
+		t.Errorf("Append on empty array keys = %v, want [0]", got)
+	}
+
+	arr = NewArray()
+	arr.Set(math.MaxInt, "a")
+	if err := arr.Append("b"); err == nil {
+		t.Error("Append after MaxInt expected an error")
+	}
+}
+
+func TestArrayValueFuncs(t *testing.T) {
+	arr := NewValue(map[string]interface{}{"a": 1, "b": "x"})
+	flip, err := ArrayFlipValue(arr)
+	if err != nil || flip.Get(1).String() != "a" || flip.Get("x").String() != "b" {
+		t.Errorf("ArrayFlipValue = %v, %v", flip, err)
+	}
+	keys, err := ArrayKeysValue(arr)
+	if err != nil || keys.Len() != 2 || !keys.IsList() {
+		t.Errorf("ArrayKeysValue = %v, %v", keys, err)
+	}
+	unique, err := ArrayUniqueValue(NewArray(1, "1", 2, 1))
+	if err != nil || unique.Len() != 2 || !unique.Has(0) || !unique.Has(2) {
+		t.Errorf("ArrayUniqueValue = %v, %v", unique, err)
+	}
+	if _, err := ArrayValuesValue("str"); err == nil {
+		t.Error("ArrayValuesValue on a string expected an error")
+	}
+}
+
+// assoc builds an array Value from key, value pairs in order
+func assoc(pairs ...interface{}) Value {
+	arr := NewArray()
+	for i := 0; i+1 < len(pairs); i += 2 {
+		arr.Set(pairs[i], pairs[i+1])
+	}
+	return arr
+}
+
+func TestValueCompare(t *testing.T) {
+	tests := []struct {
+		v, w interface{}
+		want int
+	}{
+		{nil, "", 0},
+		{nil, "a", -1},
+		{nil, 0, 0},
+		{nil, false, 0},
+		{nil, NewArray(), 0},
+		{nil, NewArray(1), -1},
+		{0, "a", -1},
+		{"abc", 0, 1},
+		{0, "", 1},
+		{"1", "01", 0},
+		{"10", "1e1", 0},
+		{100, "1e2", 0},
+		{" 1", "1", 0},
+		{"1 ", "1", 0},
+		{"abc", "abd", -1},
+		{"Z", "a", -1},
+		{1.5, 1, 1},
+		{"1.5", 1, 1},
+		{true, "a", 0},
+		{true, 0, 1},
+		{false, "0", 0},
+		{NewArray(1, 2), NewArray(1, 2, 3), -1},
+		{NewArray(1, 2, 3), NewArray(1, 2), 1},
+		{NewArray(1, 2), NewArray(1, 3), -1},
+		{NewArray(1, "2"), NewArray("1", 2), 0},
+		{assoc("a", 1, "b", 2), assoc("b", 2, "a", 1), 0},
+		{assoc("a", 1), assoc("b", 1), 1},
+		{assoc("b", 1), assoc("a", 1), 1},
+		{NewArray(), 1, 1},
+		{1, NewArray(), -1},
+		{NewArray(), "", 1},
+		{math.NaN(), math.NaN(), 1},
+		{math.Inf(1), "INF", 0},
+		{"9223372036854775807", "9223372036854775808", -1},
+		{"-9223372036854775809", "-9223372036854775808", -1},
+		{"9223372036854775808", "9223372036854775809", -1},
+		{"9223372036854775808", "9.2233720368547758e18", 0},
+		{"1e1000", "2e1000", -1},
+	}
+	for _, test := range tests {
+		v, w := NewValue(test.v), NewValue(test.w)
+		if got := v.Compare(w); got != test.want {
+			t.Errorf("%#v <=> %#v = %d, want %d", test.v, test.w, got, test.want)
+		}
+		if got := v.Equal(w); got != (test.want == 0) {
+			t.Errorf("%#v == %#v = %v, want %v", test.v, test.w, got, test.want == 0)
+		}
+	}
+
+	identical := []struct {
+		v, w interface{}
+		want bool
+	}{
+		{1, 1, true},
+		{1, 1.0, false},
+		{"1", 1, false},
+		{nil, nil, true},
+		{NewArray(1, 2), NewArray(1, 2), true},
+		{NewArray(1, 2), NewArray(1, "2"), false},
+		{assoc("a", 1, "b", 2), assoc("b", 2, "a", 1), false},
+	}
+	for _, test := range identical {
+		if got := NewValue(test.v).Identical(NewValue(test.w)); got != test.want {
+			t.Errorf("%#v === %#v = %v, want %v", test.v, test.w, got, test.want)
+		}
+	}
+}
+
+func TestValueMarshalJSON(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{nil, `null`},
+		{true, `true`},
+		{-42, `-42`},
+		{1.0, `1`},
+		{0.1, `0.1`},
+		{-0.0, `0`},
+		{1e25, `1.0e+25`},
+		{1.5e-7, `1.5e-7`},
+		{"a/b", `"a\/b"`},
+		{"\"\\\b\f\n\r\t\x01", `"\"\\\b\f\n\r\t\u0001"`},
+		{"é€", `"\u00e9\u20ac"`},
+		{"😀", `"\ud83d\ude00"`},
+		{NewArray(), `[]`},
+		{NewArray(1, "a", nil), `[1,"a",null]`},
+		{assoc(1, "a"), `{"1":"a"}`},
+		{assoc(0, "a", 2, "b"), `{"0":"a","2":"b"}`},
+		{assoc("b", 1, "a", NewArray(true)), `{"b":1,"a":[true]}`},
+	}
+	for _, test := range tests {
+		got, err := json.Marshal(NewValue(test.value))
+		if err != nil || string(got) != test.want {
+			t.Errorf("json.Marshal(%#v) = %s, %v, want %s", test.value, got, err, test.want)
+		}
+	}
+	for _, value := range []interface{}{math.NaN(), math.Inf(1), "\xff", NewArray("\xc3")} {
+		if got, err := json.Marshal(NewValue(value)); err == nil {
+			t.Errorf("json.Marshal(%#v) = %s, want an error", value, got)
+		}
+	}
+}
+
+func TestValueUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		json string
+		want Value
+	}{
+		{`null`, Value{}},
+		{`true`, NewValue(true)},
+		{`42`, NewValue(42)},
+		{`-9223372036854775808`, NewValue(math.MinInt64)},
+		{`9223372036854775808`, NewValue(9223372036854775808.0)},
+		{`1e400`, NewValue(math.Inf(1))},
+		{`1.0`, NewValue(1.0)},
+		{`"a\/b"`, NewValue("a/b")},
+		{`"é"`, NewValue("é")},
+		{`"😀"`, NewValue("😀")},
+		{`[1,"a",null,[]]`, NewArray(1, "a", nil, NewArray())},
+		{`{"b":1,"a":2}`, assoc("b", 1, "a", 2)},
+		{`{"1":"x","01":"y"}`, assoc(1, "x", "01", "y")},
+		{`{"a":1,"b":2,"a":3}`, assoc("a", 3, "b", 2)},
+		{` {"a" : [ {} ] } `, assoc("a", NewArray(NewArray()))},
+	}
+	for _, test := range tests {
+		var v Value
+		if err := json.Unmarshal([]byte(test.json), &v); err != nil || !v.Identical(test.want) {
+			t.Errorf("json.Unmarshal(%s) = %v, %v, want %v", test.json, v, err, test.want)
+		}
+	}
+	for _, data := range []string{``, `1 2`, `[1,]`, `{"a"}`, `{1:2}`, `"\ud83d"x`, `nul`} {
+		var v Value
+		if err := v.UnmarshalJSON([]byte(data)); err == nil {
+			t.Errorf("UnmarshalJSON(%s) = %v, want an error", data, v)
+		}
+	}
+
+	// a round trip keeps the keys and the order
+	arr := assoc("x", NewArray(1.5, "é/"), 7, nil, "y", assoc("z", false))
+	data, err := json.Marshal(arr)
+	var back Value
+	if err != nil || json.Unmarshal(data, &back) != nil || !back.Identical(arr) {
+		t.Errorf("JSON round trip of %v = %s, %v", arr, data, back)
+	}
+}
+
+func TestSerialize(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{nil, `N;`},
+		{true, `b:1;`},
+		{false, `b:0;`},
+		{-7, `i:-7;`},
+		{1.0, `d:1;`},
+		{0.1, `d:0.1;`},
+		{-1.5, `d:-1.5;`},
+		{1e25, `d:1.0E+25;`},
+		{math.Inf(1), `d:INF;`},
+		{math.Inf(-1), `d:-INF;`},
+		{math.NaN(), `d:NAN;`},
+		{"é", `s:2:"é";`},
+		{`a"b;`, `s:4:"a"b;";`},
+		{[]string{"a", "b"}, `a:2:{i:0;s:1:"a";i:1;s:1:"b";}`},
+		{map[string]interface{}{"a": 1, "b": []string{"x"}}, `a:2:{s:1:"a";i:1;s:1:"b";a:1:{i:0;s:1:"x";}}`},
+		{assoc(5, nil, "k", true), `a:2:{i:5;N;s:1:"k";b:1;}`},
+		{NewArray(), `a:0:{}`},
+	}
+	for _, test := range tests {
+		got := Serialize(test.value)
+		if got != test.want {
+			t.Errorf("Serialize(%#v) = %s, want %s", test.value, got, test.want)
+			continue
+		}
+		back, err := Unserialize(got)
+		want := NewValue(test.value)
+		if err != nil || !(back.Identical(want) || back.kind == KindFloat && math.IsNaN(back.f) && math.IsNaN(want.f)) {
+			t.Errorf("Unserialize(%s) = %v, %v, want %v", got, back, err, want)
+		}
+	}
+}
+
+func TestUnserialize(t *testing.T) {
+	tests := []struct {
+		str  string
+		want Value
+	}{
+		{`i:1;garbage`, NewValue(1)},
+		{`d:0.5;`, NewValue(0.5)},
+		{`d:1e3;`, NewValue(1000.0)},
+		{`a:2:{s:1:"5";i:1;s:2:"05";i:2;}`, assoc(5, 1, "05", 2)},
+		{`a:1:{s:1:"a";a:1:{i:0;N;}}`, assoc("a", NewArray(nil))},
+	}
+	for _, test := range tests {
+		if got, err := Unserialize(test.str); err != nil || !got.Identical(test.want) {
+			t.Errorf("Unserialize(%s) = %v, %v, want %v", test.str, got, err, test.want)
+		}
+	}
+
+	malformed := []string{
+		``, `N`, `i:;`, `i:abc;`, `i:1`, `b:2;`, `d:;`, `s:5:"abc";`, `s:-1:"";`, `s:1:"ab";`, `s:1:"a"`,
+		`a:1:{i:0;i:1;`, `a:2:{i:0;i:1;}`, `a:-1:{}`, `a:1:{a:0:{}i:1;}`, `a:1:{d:0.5;i:1;}`,
+		`O:8:"stdClass":0:{}`, `r:1;`, `x:1;`,
+	}
+	for _, str := range malformed {
+		if got, err := Unserialize(str); err == nil {
+			t.Errorf("Unserialize(%s) = %v, want an error", str, got)
+		}
+	}
+
+	nested := func(depth int) string {
+		return strings.Repeat("a:1:{i:0;", depth) + "N;" + strings.Repeat("}", depth)
+	}
+	if _, err := Unserialize(nested(maxUnserializeDepth)); err != nil {
+		t.Errorf("Unserialize of %d nested arrays error: %v", maxUnserializeDepth, err)
+	}
+	if _, err := Unserialize(nested(maxUnserializeDepth + 1)); err == nil {
+		t.Errorf("Unserialize of %d nested arrays expected an error", maxUnserializeDepth+1)
+	}
+}
+
+func TestValueConversions(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		b     bool
+		i     int
+		f     float64
+		s     string
+	}{
+		{nil, false, 0, 0, ""},
+		{true, true, 1, 1, "1"},
+		{false, false, 0, 0, ""},
+		{-3, true, -3, -3, "-3"},
+		{1.9, true, 1, 1.9, "1.9"},
+		{-1.9, true, -1, -1.9, "-1.9"},
+		{1e20, true, 7766279631452241920, 1e20, "1.0E+20"},
+		{math.NaN(), true, 0, math.NaN(), "NAN"},
+		{"", false, 0, 0, ""},
+		{"0", false, 0, 0, "0"},
+		{"0.0", true, 0, 0, "0.0"},
+		{" ", true, 0, 0, " "},
+		{"12abc", true, 12, 12, "12abc"},
+		{" 12", true, 12, 12, " 12"},
+		{"1e3", true, 1000, 1000, "1e3"},
+		{".5", true, 0, 0.5, ".5"},
+		{"abc", true, 0, 0, "abc"},
+		{uint64(math.MaxUint64), true, 0, 18446744073709551615, "1.844674407371E+19"},
+		{NewArray(), false, 0, 0, "Array"},
+		{NewArray(0), true, 1, 1, "Array"},
+	}
+	for _, test := range tests {
+		v := NewValue(test.value)
+		if got := v.Bool(); got != test.b {
+			t.Errorf("NewValue(%#v).Bool() = %v, want %v", test.value, got, test.b)
+		}
+		if got := v.Int(); got != test.i {
+			t.Errorf("NewValue(%#v).Int() = %d, want %d", test.value, got, test.i)
+		}
+		if got := v.Float(); got != test.f && !(math.IsNaN(got) && math.IsNaN(test.f)) {
+			t.Errorf("NewValue(%#v).Float() = %v, want %v", test.value, got, test.f)
+		}
+		if got := v.String(); got != test.s {
+			t.Errorf("NewValue(%#v).String() = %q, want %q", test.value, got, test.s)
+		}
+	}
+
+	if got := NewValue(nil).ToArray(); !got.Identical(NewArray()) {
+		t.Errorf("ToArray(null) = %v", got)
+	}
+	if got := NewValue("a").ToArray(); !got.Identical(NewArray("a")) {
+		t.Errorf("ToArray(\"a\") = %v", got)
+	}
+	if got := NewArray(1, 2).Interface(); !reflect.DeepEqual(got, []interface{}{1, 2}) {
+		t.Errorf("Interface() of a list = %#v", got)
+	}
+	if got := assoc("a", 1, 3, NewArray("x")).Interface(); !reflect.DeepEqual(got, map[interface{}]interface{}{"a": 1, 3: []interface{}{"x"}}) {
+		t.Errorf("Interface() of an array = %#v", got)
+	}
+}
+
+type valueTestStruct struct {
+	Name    string `json:"name"`
+	Skipped int    `json:"-"`
+	Tags    []string
+	private int
+}
+
+type valueTestNode struct {
+	Name string
+	Next *valueTestNode
+}
+
+type valueTestStringer struct{ n int }
+
+func (s *valueTestStringer) String() string { return "stringer" }
+
+func TestNewValue(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  Value
+	}{
+		{int8(-5), NewValue(-5)},
+		{uint(7), NewValue(7)},
+		{uint64(1 << 63), NewValue(float64(1 << 63))},
+		{float32(0.5), NewValue(0.5)},
+		{[]byte("ab"), NewValue("ab")},
+		{[2]int{1, 2}, NewArray(1, 2)},
+		{map[interface{}]int{"b": 2, 1: 1, "a": 0, "10": 3}, assoc(1, 1, 10, 3, "a", 0, "b", 2)},
+		{map[float64]int{1.5: 1}, assoc(1, 1)},
+		{valueTestStruct{Name: "n", Skipped: 1, Tags: []string{"x"}, private: 2}, assoc("name", "n", "Tags", NewArray("x"))},
+		{&valueTestStruct{Name: "p"}, assoc("name", "p", "Tags", NewArray())},
+		{time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), NewValue("2024-01-02 03:04:05 +0000 UTC")},
+		{&valueTestStringer{}, NewValue("stringer")},
+		{valueTestStringer{}, NewArray()},
+		{(*valueTestStruct)(nil), Value{}},
+		{make(chan int), Value{}},
+	}
+	for _, test := range tests {
+		if got := NewValue(test.value); !got.Identical(test.want) {
+			t.Errorf("NewValue(%#v) = %v, want %v", test.value, got, test.want)
+		}
+	}
+
+	// cycles are null, shared values which are not cycles are converted every time
+	node := &valueTestNode{Name: "a"}
+	node.Next = node
+	if got, want := NewValue(node), assoc("Name", "a", "Next", nil); !got.Identical(want) {
+		t.Errorf("NewValue of a cyclic pointer = %v, want %v", got, want)
+	}
+	list := []interface{}{1, nil}
+	list[1] = list
+	if got, want := NewValue(list), NewArray(1, nil); !got.Identical(want) {
+		t.Errorf("NewValue of a cyclic slice = %v, want %v", got, want)
+	}
+	m := map[string]interface{}{"a": 1}
+	m["self"] = m
+	if got, want := NewValue(m), assoc("a", 1, "self", nil); !got.Identical(want) {
+		t.Errorf("NewValue of a cyclic map = %v, want %v", got, want)
+	}
+	shared := []int{1}
+	if got, want := NewValue([]interface{}{shared, shared}), NewArray(NewArray(1), NewArray(1)); !got.Identical(want) {
+		t.Errorf("NewValue of shared slices = %v, want %v", got, want)
+	}
+}
+
+func TestValueCopyOnWrite(t *testing.T) {
+	a := NewArray(1, 2)
+	b := a
+	b.Set(5, 2)
+	if a.Len() != 2 || b.Len() != 3 {
+		t.Errorf("after b := a; b.Set, len(a) = %d, len(b) = %d", a.Len(), b.Len())
+	}
+
+	c := NewValue(a)
+	c.Append(3)
+	d := a.ToArray()
+	d.Delete(0)
+	e := NewValue(&a)
+	e.Set(0, "e")
+	if a.Len() != 2 || a.Get(0).Int() != 1 || c.Len() != 3 || d.Len() != 1 || e.Get(0).String() != "e" {
+		t.Errorf("copies changed a = %v, c = %v, d = %v, e = %v", a, c, d, e)
+	}
+
+	inner := NewArray(1)
+	outer := NewArray()
+	outer.Set("x", inner)
+	outer.Set("y", &inner)
+	inner.Append(2)
+	x := outer.Get("x")
+	x.Append(3)
+	if inner.Len() != 2 || outer.Get("x").Len() != 1 || outer.Get("y").Len() != 1 || x.Len() != 2 {
+		t.Errorf("inner = %v, outer = %v, x = %v", inner, outer, x)
+	}
+
+	// the Value which made the last change keeps changing its entries in place
+	for i := 0; i < 1000; i++ {
+		a.Append(i)
+	}
+	if a.Len() != 1002 || b.Len() != 3 {
+		t.Errorf("len(a) = %d, len(b) = %d", a.Len(), b.Len())
+	}
+}
+
+// valueTestDriver is a database/sql driver registered as "mysql" for the Value methods of DB,
+// it answers the wait_timeout of setExpired and the rows of the table `user`, other tables are empty
+type valueTestDriver struct{}
+
+type valueTestConn struct{}
+
+type valueTestStmt string
+
+type valueTestResult struct{}
+
+type valueTestRows struct {
+	cols []string
+	rows [][]driver.Value
+}
+
+// valueTestExec holds the query and args of the last Exec
+var valueTestExec struct {
+	query string
+	args  []driver.Value
+}
+
+func init() {
+	sql.Register(DriverName, valueTestDriver{})
+}
+
+func (valueTestDriver) Open(name string) (driver.Conn, error) { return valueTestConn{}, nil }
+
+func (valueTestConn) Prepare(query string) (driver.Stmt, error) { return valueTestStmt(query), nil }
+func (valueTestConn) Close() error                              { return nil }
+func (valueTestConn) Begin() (driver.Tx, error)                 { return nil, errors.New("no transactions") }
+
+func (s valueTestStmt) Close() error  { return nil }
+func (s valueTestStmt) NumInput() int { return -1 }
+
+func (s valueTestStmt) Exec(args []driver.Value) (driver.Result, error) {
+	valueTestExec.query, valueTestExec.args = string(s), args
+	return valueTestResult{}, nil
+}
+
+func (s valueTestStmt) Query(args []driver.Value) (driver.Rows, error) {
+	query := string(s)
+	switch {
+	case strings.HasPrefix(query, "show variables"):
+		return &valueTestRows{[]string{"Variable_name", "Value"}, [][]driver.Value{{"wait_timeout", int64(28800)}}}, nil
+	case strings.Contains(query, "`missing`"):
+		return nil, errors.New("table missing doesn't exist")
+	case strings.Contains(query, "`user`"):
+		rows := [][]driver.Value{
+			{int64(1), []byte("apple"), int64(12), nil},
+			{int64(2), []byte("king"), 1.5, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		}
+		if strings.HasSuffix(query, "LIMIT 1") {
+			rows = rows[:1]
+		}
+		return &valueTestRows{[]string{"id", "name", "age", "note"}, rows}, nil
+	}
+	return &valueTestRows{[]string{"id"}, nil}, nil
+}
+
+func (valueTestResult) LastInsertId() (int64, error) { return 7, nil }
+func (valueTestResult) RowsAffected() (int64, error) { return 1, nil }
+
+func (r *valueTestRows) Columns() []string { return r.cols }
+func (r *valueTestRows) Close() error      { return nil }
+
+func (r *valueTestRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func TestDBValue(t *testing.T) {
+	LogSQL(func(string) {})
+	db, err := Instance("value_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	apple := assoc("id", 1, "name", "apple", "age", 12, "note", nil)
+	king := assoc("id", 2, "name", "king", "age", 1.5, "note", "2024-01-02 03:04:05")
+
+	if rows, err := db.From("user").SelectValue(); err != nil || !rows.Identical(NewArray(apple, king)) {
+		t.Errorf("SelectValue() = %v, %v, want %v", rows, err, NewArray(apple, king))
+	}
+	if rows, err := db.SelectValue("SELECT * FROM `user`"); err != nil || rows.Len() != 2 {
+		t.Errorf("SelectValue(query) = %v, %v", rows, err)
+	}
+	if rows, err := db.From("empty").SelectValue(); err != nil || !rows.Identical(NewArray()) {
+		t.Errorf("SelectValue() of no rows = %v, %v, want an empty array", rows, err)
+	}
+	if _, err := db.From("missing").SelectValue(); err == nil {
+		t.Error("SelectValue() of a missing table expected an error")
+	}
+
+	row, err := db.From("user").FindValue()
+	if err != nil || !row.Identical(apple) {
+		t.Errorf("FindValue() = %v, %v, want %v", row, err, apple)
+	}
+	if q := db.GetLastSQL(); q != "SELECT * FROM `user` LIMIT 1" {
+		t.Errorf("FindValue() query %q", q)
+	}
+	if row, err := db.From("empty").FindValue(); err != nil || !row.IsNull() {
+		t.Errorf("FindValue() of no rows = %v, %v, want null", row, err)
+	}
+
+	id, err := db.From("user").InsertValue([]string{"name", "age", "note"}, NewArray(assoc("name", "apple", "age", 12, "note", nil)))
+	if err != nil || id != 7 {
+		t.Errorf("InsertValue() = %d, %v, want 7", id, err)
+	}
+	if want := "INSERT INTO `user` (`name`,`age`,`note`) VALUE (?,?,NULL)"; valueTestExec.query != want ||
+		!reflect.DeepEqual(valueTestExec.args, []driver.Value{"apple", int64(12)}) {
+		t.Errorf("InsertValue() exec %q %v, want %q [apple 12]", valueTestExec.query, valueTestExec.args, want)
+	}
+
+	n, err := db.From("user").UpdateValue(assoc("name", "king", "note", nil))
+	if err != nil || n != 1 {
+		t.Errorf("UpdateValue() = %d, %v, want 1", n, err)
+	}
+	if want := "UPDATE `user` SET `name` = ?"; valueTestExec.query != want || !reflect.DeepEqual(valueTestExec.args, []driver.Value{"king"}) {
+		t.Errorf("UpdateValue() exec %q %v, want %q [king]", valueTestExec.query, valueTestExec.args, want)
+	}
+
+	if _, err := db.From("user").InsertValue([]string{"name"}, NewValue("apple")); err == nil {
+		t.Error("InsertValue() of a string expected an error")
+	}
+	if _, err := db.From("user").InsertValue([]string{"name"}, NewArray("apple")); err == nil {
+		t.Error("InsertValue() of a string row expected an error")
+	}
+	if _, err := db.From("user").UpdateValue(NewValue(1)); err == nil {
+		t.Error("UpdateValue() of an int expected an error")
+	}
+	if _, err := db.From("user").UpdateValue(assoc("name", NewArray("a"))); err == nil {
+		t.Error("UpdateValue() of an array column expected an error")
+	}
+	if len(db.tables) != 0 {
+		t.Errorf("the tables of the failed queries are kept: %v", db.tables)
+	}
+}
@@ -0,0 +1,429 @@
+package php
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// Intval get the integer value of a variable with PHP's type juggling
+//
+// Strings use their leading numeric part, floats are truncated and booleans are 0 or 1. base only
+// applies to strings, 0 detects the base from the prefix "0x", "0b" or "0" like PHP.
+// .eg Intval("12abc") returns 12, Intval("1e3") returns 1000, Intval("42", 8) returns 34,
+// Intval("0x1A", 16) returns 26, Intval("0b11", 0) returns 3, Intval(42.99) returns 42
+//
+// see http://php.net/manual/en/function.intval.php
+func Intval(value interface{}, base ...int) int {
+	str, ok := value.(string)
+	if !ok || len(base) == 0 || base[0] == 10 {
+		return toInt(value)
+	}
+	if base[0] == 0 || base[0] == 2 {
+		s := trimNumericSpace(str)
+		if len(s) > 2 {
+			sign := 0
+			if s[0] == '-' || s[0] == '+' {
+				sign = 1
+			}
+			if s[sign] == '0' && (s[sign+1] == 'b' || s[sign+1] == 'B') {
+				return strtolBase(s[:sign]+s[sign+2:], 2)
+			}
+		}
+	}
+	return strtolBase(str, base[0])
+}
+
+// Floatval get the float value of a variable with PHP's type juggling
+// .eg Floatval("122.34343The") returns 122.34343, Floatval("1e3abc") returns 1000, Floatval("abc") returns 0
+//
+// see http://php.net/manual/en/function.floatval.php
+func Floatval(value interface{}) float64 {
+	return toFloat(value)
+}
+
+// Boolval get the boolean value of a variable with PHP's type juggling
+//
+// false, 0, 0.0, "", "0", nil, nil pointers and empty slices, arrays and maps are false,
+// everything else is true, including "0.0", " " and NaN.
+//
+// see http://php.net/manual/en/function.boolval.php
+func Boolval(value interface{}) bool {
+	switch x := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return x
+	case string:
+		return x != "" && x != "0"
+	case []byte:
+		return len(x) > 0 && string(x) != "0"
+	case Value:
+		return x.Bool()
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int() != 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint() != 0
+	case reflect.Float32, reflect.Float64:
+		return rv.Float() != 0
+	case reflect.String:
+		return rv.String() != "" && rv.String() != "0"
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return rv.Len() > 0
+	case reflect.Ptr, reflect.Interface:
+		return !rv.IsNil() && Boolval(rv.Elem().Interface())
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return !rv.IsNil()
+	}
+	return true
+}
+
+// Strval get the string value of a variable with PHP's type juggling
+//
+// true is "1", false and nil are "". Floats are formatted like PHP with precision significant digits,
+// 14 by default which is PHP's precision ini setting used by echo and string casts, 17 is the old
+// serialize_precision and -1 is the shortest string that parses back to the same float.
+// .eg Strval(1/3.0) returns "0.33333333333333", Strval(1/3.0, 17) returns "0.33333333333333331",
+// Strval(1e25) returns "1.0E+25", Strval(0.1, -1) returns "0.1"
+//
+// see http://php.net/manual/en/function.strval.php
+func Strval(value interface{}, precision ...int) string {
+	if len(precision) > 0 {
+		if rv := reflect.ValueOf(value); rv.Kind() == reflect.Float32 || rv.Kind() == reflect.Float64 {
+			return formatFloat(rv.Float(), precision[0])
+		}
+	}
+	return toString(value)
+}
+
+// IsNumeric finds whether a variable is a number or a numeric string
+//
+// Numeric strings are decimal integers or floats with an optional sign and exponent, leading and
+// trailing whitespace is allowed like PHP 8, hexadecimal and binary notations are not numeric.
+// .eg IsNumeric("1e3") returns true, IsNumeric(" 42 ") returns true, IsNumeric("0x1A") returns false, IsNumeric("12abc") returns false
+//
+// see http://php.net/manual/en/function.is-numeric.php
+func IsNumeric(value interface{}) bool {
+	var s string
+	switch x := value.(type) {
+	case string:
+		s = x
+	case []byte:
+		s = string(x)
+	case Value:
+		return IsNumeric(x.Interface())
+	default:
+		rv := reflect.ValueOf(value)
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+			reflect.Float32, reflect.Float64:
+			return true
+		case reflect.String:
+			s = rv.String()
+		case reflect.Ptr, reflect.Interface:
+			return !rv.IsNil() && IsNumeric(rv.Elem().Interface())
+		default:
+			return false
+		}
+	}
+	end, _ := numericPrefix(s)
+	if end == 0 {
+		return false
+	}
+	for ; end < len(s); end++ {
+		if !isSpace(s[end]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Empty determine whether a variable is empty, it's the opposite of Boolval so "0" is empty
+//
+// see http://php.net/manual/en/function.empty.php
+func Empty(value interface{}) bool {
+	return !Boolval(value)
+}
+
+// toInt converts v to int like PHP's (int) cast
+//
+// Floats are truncated, out of range floats wrap around like PHP on 64-bit platforms, and
+// strings use their leading numeric part, so "12abc" is 12, "1e3" is 1000 and "abc" is 0.
+func toInt(v interface{}) int {
+	switch x := v.(type) {
+	case nil:
+		return 0
+	case int:
+		return x
+	case string:
+		n, isFloat, f := parseNumeric(x)
+		if !isFloat {
+			return n
+		}
+		switch {
+		case math.IsNaN(f):
+			return 0
+		case f >= math.MaxInt64:
+			return math.MaxInt64
+		case f <= math.MinInt64:
+			return math.MinInt64
+		}
+		return int(f)
+	case []byte:
+		return toInt(string(x))
+	case Value:
+		return x.Int()
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Bool:
+		if rv.Bool() {
+			return 1
+		}
+		return 0
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return int(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return floatToInt(rv.Float())
+	case reflect.String:
+		return toInt(rv.String())
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return 0
+		}
+		return toInt(rv.Elem().Interface())
+	case reflect.Map, reflect.Slice, reflect.Array:
+		if rv.Len() > 0 {
+			return 1
+		}
+		return 0
+	}
+	return 1
+}
+
+// floatToInt converts f to int like PHP, NaN and Inf are 0 and out of range floats wrap around
+func floatToInt(f float64) int {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0
+	}
+	if f >= math.MinInt64 && f < math.MaxInt64 {
+		return int(f)
+	}
+	m := math.Mod(math.Trunc(f), 1<<64)
+	if m < 0 {
+		m += 1 << 64
+	}
+	return int(uint64(m))
+}
+
+// toFloat converts v to float64 like PHP's (float) cast
+func toFloat(v interface{}) float64 {
+	switch x := v.(type) {
+	case nil:
+		return 0
+	case float64:
+		return x
+	case string:
+		n, isFloat, f := parseNumeric(x)
+		if isFloat {
+			return f
+		}
+		return float64(n)
+	case []byte:
+		return toFloat(string(x))
+	case Value:
+		return x.Float()
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint())
+	case reflect.String:
+		return toFloat(rv.String())
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return 0
+		}
+		return toFloat(rv.Elem().Interface())
+	}
+	return float64(toInt(v))
+}
+
+// toString converts v to string like PHP's (string) cast
+//
+// true is "1", false and nil are "", floats are formatted with 14 significant digits.
+func toString(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case []byte:
+		return string(x)
+	case fmt.Stringer:
+		return x.String()
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Bool:
+		if rv.Bool() {
+			return "1"
+		}
+		return ""
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return formatFloat(rv.Float(), 14)
+	case reflect.String:
+		return rv.String()
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return ""
+		}
+		return toString(rv.Elem().Interface())
+	}
+	return fmt.Sprint(v)
+}
+
+// parseNumeric parses the leading numeric part of str like PHP, leading whitespace is allowed.
+// It returns the int n, or the float f if the number has a fraction or an exponent or overflows int.
+func parseNumeric(str string) (n int, isFloat bool, f float64) {
+	end, isFloat := numericPrefix(str)
+	s := str[:end]
+	if !isFloat {
+		i, err := strconv.ParseInt(trimNumericSpace(s), 10, 64)
+		if err == nil {
+			return int(i), false, 0
+		}
+		if s == "" || trimNumericSpace(s) == "" {
+			return 0, false, 0
+		}
+	}
+	f, _ = strconv.ParseFloat(trimNumericSpace(s), 64)
+	return 0, true, f
+}
+
+// numericPrefix returns the length of the leading numeric part of str, including the leading
+// whitespace, and whether it's a float like "1.5" or "2e3"
+func numericPrefix(str string) (end int, isFloat bool) {
+	i := 0
+	for i < len(str) && isSpace(str[i]) {
+		i++
+	}
+	if i < len(str) && (str[i] == '+' || str[i] == '-') {
+		i++
+	}
+	digits := 0
+	for ; i < len(str) && '0' <= str[i] && str[i] <= '9'; i++ {
+		digits++
+	}
+	if i < len(str) && str[i] == '.' {
+		j, frac := i+1, 0
+		for ; j < len(str) && '0' <= str[j] && str[j] <= '9'; j++ {
+			frac++
+		}
+		if digits+frac > 0 {
+			i, digits, isFloat = j, digits+frac, true
+		}
+	}
+	if digits == 0 {
+		return 0, false
+	}
+	if i < len(str) && (str[i] == 'e' || str[i] == 'E') {
+		j := i + 1
+		if j < len(str) && (str[j] == '+' || str[j] == '-') {
+			j++
+		}
+		if j < len(str) && '0' <= str[j] && str[j] <= '9' {
+			for j < len(str) && '0' <= str[j] && str[j] <= '9' {
+				j++
+			}
+			i, isFloat = j, true
+		}
+	}
+	return i, isFloat
+}
+
+// trimNumericSpace strips the leading whitespace of a numeric string
+func trimNumericSpace(s string) string {
+	for len(s) > 0 && isSpace(s[0]) {
+		s = s[1:]
+	}
+	return s
+}
+
+// strtolBase parses the leading integer of str in base like C's strtol, the base 0 detects the base
+// from the prefix "0x" or "0", overflows saturate and an invalid base returns 0
+func strtolBase(str string, base int) int {
+	if base < 0 || base == 1 || base > 36 {
+		return 0
+	}
+	s := trimNumericSpace(str)
+	neg := false
+	if s != "" && (s[0] == '+' || s[0] == '-') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	switch {
+	case (base == 0 || base == 16) && len(s) > 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') && digitValue(s[2]) < 16:
+		s, base = s[2:], 16
+	case base == 0 && s != "" && s[0] == '0':
+		base = 8
+	case base == 0:
+		base = 10
+	}
+
+	const limit = 1 << 63
+	var n uint64
+	overflow := false
+	for i := 0; i < len(s); i++ {
+		d := digitValue(s[i])
+		if d >= base {
+			break
+		}
+		if n > (limit-uint64(d))/uint64(base) {
+			overflow = true
+		} else {
+			n = n*uint64(base) + uint64(d)
+		}
+	}
+	switch {
+	case neg && overflow:
+		return math.MinInt64
+	case neg:
+		return int(-n)
+	case overflow || n > math.MaxInt64:
+		return math.MaxInt64
+	}
+	return int(n)
+}
+
+// digitValue returns the value of the digit c in bases up to 36, 36 if c is not a digit
+func digitValue(c byte) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0')
+	case c >= 'a' && c <= 'z':
+		return int(c-'a') + 10
+	case c >= 'A' && c <= 'Z':
+		return int(c-'A') + 10
+	}
+	return 36
+}
@@ -0,0 +1,174 @@
+package php
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIntval(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		base  []int
+		want  int
+	}{
+		{42, nil, 42},
+		{4.2, nil, 4},
+		{"42", nil, 42},
+		{"+42", nil, 42},
+		{"-42", nil, -42},
+		{"042", nil, 42},
+		{" 12abc", nil, 12},
+		{"1e10", nil, 10000000000},
+		{"0x1A", nil, 0},
+		{1e10, nil, 10000000000},
+		{4.2e20, nil, -4275113695319687168},
+		{"420000000000000000000", nil, math.MaxInt64},
+		{"-420000000000000000000", nil, math.MinInt64},
+		{[]string{}, nil, 0},
+		{[]string{"foo", "bar"}, nil, 1},
+		{true, nil, 1},
+		{false, nil, 0},
+		{nil, nil, 0},
+		{42, []int{8}, 42},
+		{"42", []int{8}, 34},
+		{"0x1A", []int{16}, 26},
+		{"1A", []int{16}, 26},
+		{"-0x1a", []int{16}, -26},
+		{"0x1A", []int{0}, 26},
+		{"042", []int{0}, 34},
+		{"42", []int{0}, 42},
+		{"0b11", []int{0}, 3},
+		{"-0b11", []int{2}, -3},
+		{"11", []int{2}, 3},
+		{"0b", []int{0}, 0},
+		{"z", []int{36}, 35},
+		{"7FFFFFFFFFFFFFFFFF", []int{16}, math.MaxInt64},
+		{"-7FFFFFFFFFFFFFFFFF", []int{16}, math.MinInt64},
+	}
+	for _, test := range tests {
+		if got := Intval(test.value, test.base...); got != test.want {
+			t.Errorf("Intval(%#v, %v) = %d, want %d", test.value, test.base, got, test.want)
+		}
+	}
+}
+
+func TestFloatval(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  float64
+	}{
+		{"122.34343The", 122.34343},
+		{"1e3abc", 1000},
+		{"abc", 0},
+		{"-.5", -0.5},
+		{" 1.5", 1.5},
+		{"1.", 1},
+		{true, 1},
+		{int8(-3), -3},
+	}
+	for _, test := range tests {
+		if got := Floatval(test.value); got != test.want {
+			t.Errorf("Floatval(%#v) = %v, want %v", test.value, got, test.want)
+		}
+	}
+}
+
+func TestBoolval(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  bool
+	}{
+		{"0", false},
+		{"", false},
+		{"0.0", true},
+		{" ", true},
+		{0, false},
+		{0.0, false},
+		{math.NaN(), true},
+		{[]int{}, false},
+		{[]int{0}, true},
+		{map[string]int{}, false},
+		{nil, false},
+		{(*int)(nil), false},
+	}
+	for _, test := range tests {
+		if got := Boolval(test.value); got != test.want {
+			t.Errorf("Boolval(%#v) = %v, want %v", test.value, got, test.want)
+		}
+		if got := Empty(test.value); got == test.want {
+			t.Errorf("Empty(%#v) = %v, want %v", test.value, got, !test.want)
+		}
+	}
+}
+
+func TestStrval(t *testing.T) {
+	point1, point2 := 0.1, 0.2
+	tests := []struct {
+		value     interface{}
+		precision []int
+		want      string
+	}{
+		{1 / 3.0, nil, "0.33333333333333"},
+		{1 / 3.0, []int{17}, "0.33333333333333331"},
+		{1 / 3.0, []int{-1}, "0.3333333333333333"},
+		{point1 + point2, nil, "0.3"},
+		{point1 + point2, []int{17}, "0.30000000000000004"},
+		{point1 + point2, []int{-1}, "0.30000000000000004"},
+		{0.1, []int{-1}, "0.1"},
+		{1e25, nil, "1.0E+25"},
+		{1e14, nil, "1.0E+14"},
+		{1e20, []int{-1}, "1.0E+20"},
+		{100000.0, nil, "100000"},
+		{1.5e-7, nil, "1.5E-7"},
+		{-1.5, nil, "-1.5"},
+		{123456789012345678.0, []int{17}, "1.2345678901234568E+17"},
+		{math.Inf(1), nil, "INF"},
+		{math.Inf(-1), nil, "-INF"},
+		{math.NaN(), nil, "NAN"},
+		{true, nil, "1"},
+		{false, nil, ""},
+		{nil, nil, ""},
+		{42, []int{17}, "42"},
+		{"abc", nil, "abc"},
+	}
+	for _, test := range tests {
+		if got := Strval(test.value, test.precision...); got != test.want {
+			t.Errorf("Strval(%#v, %v) = %q, want %q", test.value, test.precision, got, test.want)
+		}
+	}
+}
+
+func TestIsNumeric(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  bool
+	}{
+		{"42", true},
+		{"1.", true},
+		{".5", true},
+		{"-.5", true},
+		{"+1.5e-3", true},
+		{"1e5", true},
+		{" 1", true},
+		{"1 ", true},
+		{"\t\n1\r\v\f", true},
+		{"1e", false},
+		{"1e+", false},
+		{".", false},
+		{"", false},
+		{" ", false},
+		{"0x1A", false},
+		{"0b11", false},
+		{"1_000", false},
+		{"12abc", false},
+		{42, true},
+		{1.5, true},
+		{true, false},
+		{nil, false},
+	}
+	for _, test := range tests {
+		if got := IsNumeric(test.value); got != test.want {
+			t.Errorf("IsNumeric(%#v) = %v, want %v", test.value, got, test.want)
+		}
+	}
+}
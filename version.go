@@ -0,0 +1,208 @@
+package php
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// versionForms is the order of the special version forms, a part of a version starting with one
+// of the names has its order, "#" stands for any number and unknown forms are before "dev"
+var versionForms = []struct {
+	name  string
+	order int
+}{
+	{"dev", 0}, {"alpha", 1}, {"a", 1}, {"beta", 2}, {"b", 2}, {"RC", 3}, {"rc", 3}, {"#", 4}, {"pl", 5}, {"p", 5},
+}
+
+// VersionCompare compares two "PHP-standardized" version number strings, it returns -1 if version1 is
+// lower than version2, 0 if they are equal and 1 if version1 is higher
+//
+// The versions are canonicalized first: "-", "_" and "+" are replaced with "." and a "." is inserted
+// between numbers and letters, so "1.0rc1" is "1.0.rc.1". The parts are compared from left to right,
+// numbers numerically and the special forms in the order any unknown string < "dev" < "alpha" = "a"
+// < "beta" = "b" < "RC" = "rc" < numbers < "pl" = "p".
+// .eg VersionCompare("5.2", "5.10") returns -1, VersionCompare("1.0rc1", "1.0") returns -1,
+// VersionCompare("1.0", "1.0.0") returns -1, VersionCompare("1.0pl1", "1.0") returns 1
+//
+// see http://php.net/manual/en/function.version-compare.php
+func VersionCompare(version1, version2 string) int {
+	if version1 == "" || version2 == "" {
+		switch {
+		case version1 == version2:
+			return 0
+		case version1 != "":
+			return 1
+		}
+		return -1
+	}
+	if version1[0] != '#' {
+		version1 = canonicalizeVersion(version1)
+	}
+	if version2[0] != '#' {
+		version2 = canonicalizeVersion(version2)
+	}
+
+	compare := 0
+	p1, p2 := 0, 0
+	more1, more2 := true, true
+	for p1 < len(version1) && p2 < len(version2) && more1 && more2 {
+		e1, e2 := strings.IndexByte(version1[p1:], '.'), strings.IndexByte(version2[p2:], '.')
+		if more1 = e1 >= 0; more1 {
+			e1 += p1
+		} else {
+			e1 = len(version1)
+		}
+		if more2 = e2 >= 0; more2 {
+			e2 += p2
+		} else {
+			e2 = len(version2)
+		}
+		part1, part2 := version1[p1:e1], version2[p2:e2]
+		switch digit1, digit2 := part1 != "" && isDigit(part1[0]), part2 != "" && isDigit(part2[0]); {
+		case digit1 && digit2:
+			n1, n2 := strtolBase(part1, 10), strtolBase(part2, 10)
+			if n1 < n2 {
+				compare = -1
+			} else if n1 > n2 {
+				compare = 1
+			}
+		case !digit1 && !digit2:
+			compare = compareVersionForms(part1, part2)
+		case digit1:
+			compare = compareVersionForms("#N#", part2)
+		default:
+			compare = compareVersionForms(part1, "#N#")
+		}
+		if compare != 0 {
+			break
+		}
+		if more1 {
+			p1 = e1 + 1
+		}
+		if more2 {
+			p2 = e2 + 1
+		}
+	}
+
+	if compare == 0 {
+		// the remaining parts of the longer version decide
+		if more1 {
+			if p1 < len(version1) && isDigit(version1[p1]) {
+				compare = 1
+			} else {
+				compare = VersionCompare(version1[p1:], "#N#")
+			}
+		} else if more2 {
+			if p2 < len(version2) && isDigit(version2[p2]) {
+				compare = -1
+			} else {
+				compare = VersionCompare("#N#", version2[p2:])
+			}
+		}
+	}
+	return compare
+}
+
+// VersionCompareOperator compares two "PHP-standardized" version number strings with operator like
+// PHP's version_compare with 3 arguments, see VersionCompare for the order
+//
+// operator is one of "<", "lt", "<=", "le", ">", "gt", ">=", "ge", "==", "eq", "!=", "<>" and "ne",
+// it returns an error for any other operator like PHP's ValueError.
+// .eg VersionCompareOperator("8.1.0", "8.0.30", ">=") returns true, VersionCompareOperator("1.0.0-beta", "1.0.0", "lt") returns true
+//
+// see http://php.net/manual/en/function.version-compare.php
+func VersionCompareOperator(version1, version2, operator string) (bool, error) {
+	compare := VersionCompare(version1, version2)
+	switch operator {
+	case "<", "lt":
+		return compare == -1, nil
+	case "<=", "le":
+		return compare != 1, nil
+	case ">", "gt":
+		return compare == 1, nil
+	case ">=", "ge":
+		return compare != -1, nil
+	case "==", "eq":
+		return compare == 0, nil
+	case "!=", "<>", "ne":
+		return compare != 0, nil
+	}
+	return false, fmt.Errorf("invalid comparison operator %q", operator)
+}
+
+// VersionSlice attaches the methods of sort.Interface to []string, sorting in increasing order of
+// VersionCompare
+type VersionSlice []string
+
+func (x VersionSlice) Len() int           { return len(x) }
+func (x VersionSlice) Less(i, j int) bool { return VersionCompare(x[i], x[j]) < 0 }
+func (x VersionSlice) Swap(i, j int)      { x[i], x[j] = x[j], x[i] }
+
+// SortVersions sorts a slice of version strings in increasing order of VersionCompare, the equal
+// versions like "1.0-rc1" and "1.0RC1" keep their original order
+// .eg SortVersions(versions) sorts ["1.10", "1.0rc1", "1.9", "1.0"] to ["1.0rc1", "1.0", "1.9", "1.10"]
+func SortVersions(versions []string) {
+	sort.Stable(VersionSlice(versions))
+}
+
+// canonicalizeVersion is the port of PHP's php_canonicalize_version, "-", "_", "+" and the other
+// characters which are not alphanumeric become ".", and a "." is inserted between a digit and
+// something else than a digit or "."
+func canonicalizeVersion(version string) string {
+	isNdig := func(c byte) bool { return !isDigit(c) && c != '.' }
+	b := make([]byte, 1, len(version)*2)
+	b[0] = version[0]
+	lp := version[0]
+	for i := 1; i < len(version); i++ {
+		c := version[i]
+		switch {
+		case c == '-' || c == '_' || c == '+':
+			if b[len(b)-1] != '.' {
+				b = append(b, '.')
+			}
+		case isNdig(lp) && isDigit(c) || isDigit(lp) && isNdig(c):
+			if b[len(b)-1] != '.' {
+				b = append(b, '.')
+			}
+			b = append(b, c)
+		case !isAlnum(c):
+			if b[len(b)-1] != '.' {
+				b = append(b, '.')
+			}
+		default:
+			b = append(b, c)
+		}
+		lp = c
+	}
+	return string(b)
+}
+
+// compareVersionForms compares two special version forms like PHP's compare_special_version_forms
+func compareVersionForms(form1, form2 string) int {
+	order1, order2 := -1, -1
+	for _, form := range versionForms {
+		if strings.HasPrefix(form1, form.name) {
+			order1 = form.order
+			break
+		}
+	}
+	for _, form := range versionForms {
+		if strings.HasPrefix(form2, form.name) {
+			order2 = form.order
+			break
+		}
+	}
+	switch {
+	case order1 < order2:
+		return -1
+	case order1 > order2:
+		return 1
+	}
+	return 0
+}
+
+// isDigit reports if c is an ASCII digit
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,89 @@
+package php
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVersionCompare(t *testing.T) {
+	tests := []struct {
+		version1, version2 string
+		want               int
+	}{
+		{"5.2", "5.10", -1},
+		{"1.10", "1.9", 1},
+		{"1.01", "1.1", 0},
+		{"1.0", "1.0.0", -1},
+		{"1", "1.0", -1},
+		{"1.0.0", "1.0", 1},
+		{"1.0", "1.0.0-dev", -1},
+		{"1.0rc1", "1.0.rc.1", 0},
+		{"1.0RC1", "1.0rc1", 0},
+		{"1.0-rc1", "1.0rc1", 0},
+		{"1.0_rc_1", "1.0+rc1", 0},
+		{"1.0-dev", "1.0.dev", 0},
+		{"1.0foo", "1.0dev", -1},
+		{"1.0-dev", "1.0alpha", -1},
+		{"1.0a", "1.0alpha", 0},
+		{"1.0alpha", "1.0beta", -1},
+		{"1.0b", "1.0beta", 0},
+		{"1.0beta", "1.0RC", -1},
+		{"1.0RC", "1.0.0", -1},
+		{"1.0rc1", "1.0", -1},
+		{"1.0", "1.0pl", -1},
+		{"1.0pl1", "1.0", 1},
+		{"1.0p1", "1.0pl1", 0},
+		{"", "", 0},
+		{"1", "", 1},
+		{"", "1", -1},
+	}
+	for _, test := range tests {
+		if got := VersionCompare(test.version1, test.version2); got != test.want {
+			t.Errorf("VersionCompare(%q, %q) = %d, want %d", test.version1, test.version2, got, test.want)
+		}
+		if got := VersionCompare(test.version2, test.version1); got != -test.want {
+			t.Errorf("VersionCompare(%q, %q) = %d, want %d", test.version2, test.version1, got, -test.want)
+		}
+	}
+}
+
+func TestSortVersions(t *testing.T) {
+	versions := []string{"1.10", "1.0rc1", "1.9", "1.0", "1.0-dev", "1.0pl1"}
+	SortVersions(versions)
+	if want := []string{"1.0-dev", "1.0rc1", "1.0", "1.0pl1", "1.9", "1.10"}; !reflect.DeepEqual(versions, want) {
+		t.Errorf("SortVersions = %q, want %q", versions, want)
+	}
+
+	// equal versions keep their order
+	versions = []string{"2.0", "1.0RC1", "1.0rc1", "1.0-rc1", "1.0.rc.1", "0.1"}
+	SortVersions(versions)
+	if want := []string{"0.1", "1.0RC1", "1.0rc1", "1.0-rc1", "1.0.rc.1", "2.0"}; !reflect.DeepEqual(versions, want) {
+		t.Errorf("SortVersions = %q, want %q", versions, want)
+	}
+}
+
+func TestVersionCompareOperator(t *testing.T) {
+	tests := []struct {
+		version1, version2, operator string
+		want                         bool
+	}{
+		{"8.1.0", "8.0.30", ">=", true},
+		{"1.0.0-beta", "1.0.0", "lt", true},
+		{"1.0", "1.0.0", "<", true},
+		{"1.0rc1", "1.0RC1", "eq", true},
+		{"5.2", "5.2", "<>", false},
+		{"1.10", "1.9", "gt", true},
+	}
+	for _, test := range tests {
+		got, err := VersionCompareOperator(test.version1, test.version2, test.operator)
+		if err != nil || got != test.want {
+			t.Errorf("VersionCompareOperator(%q, %q, %q) = %v, %v, want %v", test.version1, test.version2, test.operator, got, err, test.want)
+		}
+	}
+
+	for _, operator := range []string{"", "=", "===", "GE"} {
+		if got, err := VersionCompareOperator("1.0", "1.0", operator); err == nil || got {
+			t.Errorf("VersionCompareOperator(%q) = %v, %v, want an error", operator, got, err)
+		}
+	}
+}